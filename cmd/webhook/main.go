@@ -6,38 +6,157 @@ SPDX-License-Identifier: Apache-2.0
 package main
 
 import (
-	"context"
+	"crypto/tls"
 	"flag"
+	"net"
+	"strconv"
+	"time"
 
 	"github.com/pkg/errors"
 	"github.com/spf13/pflag"
 
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes"
 	_ "k8s.io/client-go/plugin/pkg/client/auth"
+	"k8s.io/client-go/tools/clientcmd"
 	"k8s.io/klog/v2"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/certwatcher"
+	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
+	ctrlwebhook "sigs.k8s.io/controller-runtime/pkg/webhook"
 
-	"github.com/sap/admission-webhook-runtime/pkg/admission"
+	"github.com/sap/secret-generator/internal/controller"
+	"github.com/sap/secret-generator/pkg/generator"
+	"github.com/sap/secret-generator/pkg/webhook"
+)
 
-	"github.com/sap/secret-generator/internal/webhook"
+const (
+	mutatePath   = "/core/v1/secret/mutate"
+	validatePath = "/core/v1/secret/validate"
 )
 
 func main() {
-	pflag.CommandLine.AddGoFlagSet(admission.FlagSet())
+	var bindAddress string
+	var tlsKeyFile string
+	var tlsCertFile string
+	var plugins []string
+	var pluginTimeout time.Duration
+	var sequenceNamespace string
+	var softFail bool
+	var lock bool
+	var unlockUsers []string
+	var unlockGroups []string
+	var gitOps bool
+	var fieldManager string
+	var enableRotationController bool
+	var leaderElect bool
+	var leaderElectionNamespace string
+	pflag.StringVar(&bindAddress, "bind-address", ":2443", "Webhook bind address")
+	pflag.StringVar(&tlsKeyFile, "tls-key-file", "", "File containing the TLS private key used for SSL termination")
+	pflag.StringVar(&tlsCertFile, "tls-cert-file", "", "File containing the TLS certificate matching the private key")
+	pflag.StringArrayVar(&plugins, "generator-plugin", nil, "Generator type served by an external executable, as <type>=<path> (may be repeated)")
+	pflag.DurationVar(&pluginTimeout, "generator-plugin-timeout", generator.DefaultPluginTimeout, "Timeout for calls to generator plugins")
+	pflag.StringVar(&sequenceNamespace, "sequence-namespace", "", "Namespace of the config map holding the counters of the sequence generator (default: namespace of the pod)")
+	pflag.BoolVar(&softFail, "soft-fail", false, "Keep placeholders of keys which cannot be generated, and record the errors in the secret, instead of denying it")
+	pflag.BoolVar(&gitOps, "gitops", false, "Enable GitOps mode, i.e. record placeholders and retain generated keys (unless disabled per secret)")
+	pflag.StringVar(&fieldManager, "gitops-field-manager", "", "Field manager taking over the ownership of generated keys in GitOps mode (default: ownership is not changed)")
+	pflag.BoolVar(&lock, "lock-generated-keys", false, "Deny updates changing generated keys to other values than placeholders (unless disabled per secret)")
+	pflag.StringSliceVar(&unlockUsers, "unlock-allowed-users", nil, "Users which may change locked keys")
+	pflag.StringSliceVar(&unlockGroups, "unlock-allowed-groups", nil, "Groups whose members may change locked keys")
+	pflag.BoolVar(&enableRotationController, "enable-rotation-controller", false, "Run the controller requesting the rotation of generated keys exceeding their max-age")
+	pflag.BoolVar(&leaderElect, "leader-elect", true, "Use leader election for the rotation controller")
+	pflag.StringVar(&leaderElectionNamespace, "leader-election-namespace", "", "Namespace of the rotation controller's leader election lease (default: namespace of the pod)")
 	klog.InitFlags(nil)
 	pflag.CommandLine.AddGoFlagSet(flag.CommandLine)
 	pflag.CommandLine.SortFlags = false
 	pflag.Parse()
 
+	if tlsKeyFile == "" || tlsCertFile == "" {
+		klog.Fatal("flags --tls-key-file and --tls-cert-file are required")
+	}
+	host, port, err := net.SplitHostPort(bindAddress)
+	if err != nil {
+		klog.Fatal(errors.Wrapf(err, "invalid bind address %s", bindAddress))
+	}
+	portNumber, err := strconv.Atoi(port)
+	if err != nil {
+		klog.Fatal(errors.Wrapf(err, "invalid bind address %s", bindAddress))
+	}
+
+	for _, spec := range plugins {
+		name, path, err := generator.ParsePluginSpec(spec)
+		if err != nil {
+			klog.Fatal(err)
+		}
+		if err := generator.RegisterGenerator(generator.NewExecGenerator(name, path, pluginTimeout)); err != nil {
+			klog.Fatal(errors.Wrapf(err, "error registering generator plugin %s", name))
+		}
+	}
+
 	scheme := runtime.NewScheme()
 	if err := corev1.AddToScheme(scheme); err != nil {
 		klog.Fatal(errors.Wrap(err, "error populating corev1 scheme"))
 	}
-	webhook := webhook.NewSecretWebhook()
-	if err := admission.RegisterMutatingWebhook[*corev1.Secret](webhook, scheme, klog.NewKlogr()); err != nil {
-		klog.Fatal(errors.Wrapf(err, "error registering webhook for corev1.Secret"))
+	clientConfig := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(clientcmd.NewDefaultClientConfigLoadingRules(), &clientcmd.ConfigOverrides{})
+	cfg, err := clientConfig.ClientConfig()
+	if err != nil {
+		klog.Fatal(errors.Wrap(err, "error loading kubeconfig"))
+	}
+	if sequenceNamespace == "" {
+		// in cluster, this is the namespace of the pod; otherwise the namespace of the current kubeconfig context
+		sequenceNamespace, _, err = clientConfig.Namespace()
+		if err != nil {
+			klog.Fatal(errors.Wrap(err, "error determining namespace"))
+		}
+	}
+	clientset, err := kubernetes.NewForConfig(cfg)
+	if err != nil {
+		klog.Fatal(errors.Wrap(err, "error creating kubernetes clientset"))
+	}
+	secretWebhook := webhook.NewSecretWebhook(webhook.WithClient(clientset), webhook.WithSequenceNamespace(sequenceNamespace), webhook.WithSoftFail(softFail), webhook.WithLock(lock), webhook.WithUnlockAllowed(unlockUsers, unlockGroups), webhook.WithGitOps(gitOps), webhook.WithFieldManager(fieldManager), webhook.WithLogger(klog.NewKlogr().WithName("secret-generator")))
+	certWatcher, err := certwatcher.New(tlsCertFile, tlsKeyFile)
+	if err != nil {
+		klog.Fatal(errors.Wrap(err, "error loading tls certificate"))
+	}
+	server := ctrlwebhook.NewServer(ctrlwebhook.Options{
+		Host: host,
+		Port: portNumber,
+		TLSOpts: []func(*tls.Config){func(config *tls.Config) {
+			config.GetCertificate = certWatcher.GetCertificate
+		}},
+	})
+	server.Register(mutatePath, webhook.NewMutatingHandler(secretWebhook))
+	server.Register(validatePath, webhook.NewValidatingHandler(secretWebhook))
+	ctrl.SetLogger(klog.NewKlogr())
+	ctx := ctrl.SetupSignalHandler()
+	go func() {
+		if err := certWatcher.Start(ctx); err != nil {
+			klog.Fatal(errors.Wrap(err, "error watching tls certificate"))
+		}
+	}()
+	if enableRotationController {
+		mgr, err := ctrl.NewManager(cfg, ctrl.Options{
+			Scheme:                  scheme,
+			Metrics:                 metricsserver.Options{BindAddress: "0"},
+			HealthProbeBindAddress:  "0",
+			LeaderElection:          leaderElect,
+			LeaderElectionID:        "secret-generator-rotation",
+			LeaderElectionNamespace: leaderElectionNamespace,
+		})
+		if err != nil {
+			klog.Fatal(errors.Wrap(err, "error creating controller manager"))
+		}
+		if err := (&controller.RotationReconciler{Client: mgr.GetClient()}).SetupWithManager(mgr); err != nil {
+			klog.Fatal(errors.Wrap(err, "error registering rotation controller"))
+		}
+		go func() {
+			if err := mgr.Start(ctx); err != nil {
+				klog.Fatal(errors.Wrap(err, "error running controller manager"))
+			}
+		}()
 	}
-	if err := admission.Serve(context.Background(), nil); err != nil {
-		klog.Fatal(errors.Wrap(err, "error running http server"))
+	if err := server.Start(ctx); err != nil {
+		klog.Fatal(errors.Wrap(err, "error running webhook server"))
 	}
 }
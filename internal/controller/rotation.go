@@ -0,0 +1,122 @@
+/*
+SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company and secret-generator contributors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package controller implements the rotation controller, which requests the rotation of generated secret keys exceeding their maximum age.
+package controller
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	"github.com/sap/secret-generator/pkg/generator"
+	"github.com/sap/secret-generator/pkg/webhook"
+)
+
+const (
+	// DefaultRetryInterval is the default time after which the rotation of keys is requested again, if they were not rotated
+	DefaultRetryInterval = 10 * time.Minute
+)
+
+// RotationReconciler watches secrets with max-age annotation, and requests the rotation of expired keys by updating the rotate annotation;
+// the rotation itself is performed by the webhook. Only the metadata of secrets is read.
+type RotationReconciler struct {
+	Client client.Client
+	// RetryInterval is the time after which the rotation is requested again, if the keys are still expired
+	// (e.g. because the webhook was not called for the secret); defaults to DefaultRetryInterval
+	RetryInterval time.Duration
+	// Registry is used to look up the generators of generated keys (the default maximum age only applies to rotatable generators);
+	// defaults to generator.DefaultRegistry
+	Registry *generator.Registry
+	// Now returns the current time; defaults to time.Now
+	Now func() time.Time
+}
+
+func (r *RotationReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := ctrl.LoggerFrom(ctx)
+
+	secret := &metav1.PartialObjectMetadata{}
+	secret.SetGroupVersionKind(corev1.SchemeGroupVersion.WithKind("Secret"))
+	if err := r.Client.Get(ctx, req.NamespacedName, secret); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+	now := r.now()
+	expired, next, err := webhook.GetExpiredKeys(secret, r.Registry, now)
+	if err != nil {
+		// retrying does not help; the secret is reconciled again once the annotation is fixed
+		log.Error(err, "error checking secret for expired keys")
+		return ctrl.Result{}, nil
+	}
+	if len(expired) == 0 {
+		if next.IsZero() {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{RequeueAfter: next.Sub(now)}, nil
+	}
+
+	// do not request the rotation again, as long as the last request is recent
+	if requestedAt := getRotationRequestTime(secret); !requestedAt.IsZero() {
+		if retryAt := requestedAt.Add(r.retryInterval()); retryAt.After(now) {
+			return ctrl.Result{RequeueAfter: retryAt.Sub(now)}, nil
+		}
+	}
+
+	patch := client.MergeFrom(secret.DeepCopy())
+	annotations := secret.GetAnnotations()
+	annotations[webhook.AnnotationKeyRotate] = strings.Join(append(expired, now.UTC().Format(time.RFC3339)), ",")
+	secret.SetAnnotations(annotations)
+	if err := r.Client.Patch(ctx, secret, patch); err != nil {
+		return ctrl.Result{}, errors.Wrap(err, "error requesting rotation of expired keys")
+	}
+	log.Info("requested rotation of expired keys", "keys", expired)
+	// the secret is reconciled again after the rotation, since the webhook updates the generated annotation;
+	// requeueing ensures the request is retried, if the keys were not rotated
+	return ctrl.Result{RequeueAfter: r.retryInterval()}, nil
+}
+
+// SetupWithManager registers the reconciler with mgr; only secrets with max-age annotation are watched.
+func (r *RotationReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		Named("secret-rotation").
+		For(&corev1.Secret{}, builder.OnlyMetadata, builder.WithPredicates(predicate.NewPredicateFuncs(func(obj client.Object) bool {
+			_, ok := obj.GetAnnotations()[webhook.AnnotationKeyMaxAge]
+			return ok
+		}))).
+		Complete(r)
+}
+
+func (r *RotationReconciler) now() time.Time {
+	if r.Now != nil {
+		return r.Now()
+	}
+	return time.Now()
+}
+
+func (r *RotationReconciler) retryInterval() time.Duration {
+	if r.RetryInterval > 0 {
+		return r.RetryInterval
+	}
+	return DefaultRetryInterval
+}
+
+// return the (latest) timestamp contained in the rotate annotation of secret, or zero if there is none
+func getRotationRequestTime(secret metav1.Object) time.Time {
+	var requestedAt time.Time
+	for _, item := range strings.Split(secret.GetAnnotations()[webhook.AnnotationKeyRotate], ",") {
+		if t, err := time.Parse(time.RFC3339, strings.TrimSpace(item)); err == nil && t.After(requestedAt) {
+			requestedAt = t
+		}
+	}
+	return requestedAt
+}
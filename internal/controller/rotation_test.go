@@ -0,0 +1,92 @@
+/*
+SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company and secret-generator contributors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/sap/secret-generator/pkg/webhook"
+)
+
+func TestReconcile(t *testing.T) {
+	now := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:         "testing",
+			Name:              "test",
+			CreationTimestamp: metav1.NewTime(now.Add(-200 * 24 * time.Hour)),
+			Annotations: map[string]string{
+				webhook.AnnotationKeyMaxAge:    "90d,tls=365d",
+				webhook.AnnotationKeyGenerated: `{"password":{"spec":"password","generatedAt":"2026-07-01T00:00:00Z"},"token":{"spec":"password","generatedAt":"2026-10-01T00:00:00Z"},"id":{"spec":"mac","generatedAt":"2026-01-01T00:00:00Z"},"tls":{"spec":"mtls","keys":["ca.crt"]}}`,
+			},
+		},
+	}
+	c := fake.NewClientBuilder().WithObjects(secret).Build()
+	r := &RotationReconciler{Client: c, Now: func() time.Time { return now }}
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Namespace: "testing", Name: "test"}}
+
+	result, err := r.Reconcile(context.TODO(), req)
+	if err != nil {
+		t.Fatalf("got error: %s", err)
+	}
+	if result.RequeueAfter != DefaultRetryInterval {
+		t.Errorf("got invalid result: %+v", result)
+	}
+	if err := c.Get(context.TODO(), req.NamespacedName, secret); err != nil {
+		t.Fatalf("got error: %s", err)
+	}
+	if v := secret.Annotations[webhook.AnnotationKeyRotate]; v != "password,2026-10-18T12:00:00Z" {
+		t.Errorf("got invalid rotate annotation: %s", v)
+	}
+
+	// no repeated request within the retry interval
+	now = now.Add(time.Minute)
+	result, err = r.Reconcile(context.TODO(), req)
+	if err != nil {
+		t.Fatalf("got error: %s", err)
+	}
+	if result.RequeueAfter != DefaultRetryInterval-time.Minute {
+		t.Errorf("got invalid result: %+v", result)
+	}
+
+	// after rotation, the reconciler waits for the next key to expire
+	secret.Annotations[webhook.AnnotationKeyGenerated] = `{"password":{"spec":"password","generatedAt":"2026-10-18T12:01:00Z"},"token":{"spec":"password","generatedAt":"2026-10-01T00:00:00Z"},"tls":{"spec":"mtls","keys":["ca.crt"]}}`
+	if err := c.Update(context.TODO(), secret); err != nil {
+		t.Fatalf("got error: %s", err)
+	}
+	result, err = r.Reconcile(context.TODO(), req)
+	if err != nil {
+		t.Fatalf("got error: %s", err)
+	}
+	if expected := time.Date(2026, 12, 30, 0, 0, 0, 0, time.UTC).Sub(now); result.RequeueAfter != expected {
+		t.Errorf("got invalid result: %+v (expected requeue after %s)", result, expected)
+	}
+}
+
+func TestReconcileWithInvalidAnnotation(t *testing.T) {
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "testing",
+			Name:      "test",
+			Annotations: map[string]string{
+				webhook.AnnotationKeyMaxAge: "forever",
+			},
+		},
+	}
+	r := &RotationReconciler{Client: fake.NewClientBuilder().WithObjects(secret).Build()}
+	result, err := r.Reconcile(context.TODO(), ctrl.Request{NamespacedName: types.NamespacedName{Namespace: "testing", Name: "test"}})
+	if err != nil || result.RequeueAfter != 0 {
+		t.Errorf("got invalid result: %+v (error: %v)", result, err)
+	}
+}
@@ -0,0 +1,396 @@
+/*
+SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company and secret-generator contributors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package generator
+
+import (
+	"context"
+	"encoding/base32"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sethvargo/go-password/password"
+)
+
+const (
+	// Symbols are the default symbols used by the password generator.
+	Symbols = `-~!@#$%^&*()_+={}|:<>?,./` // caveat: important to have - at first place (to work in regexp character sets)
+	// further symbols, which may be specified in the symbols argument of the password generator (; and " require quoting)
+	extraSymbols = ";\"'`[]\\"
+)
+
+// built-in generators, registered in the default registry
+var builtinGenerators = []Generator{
+	NewGenerator("password", Schema{
+		Arguments: []Argument{
+			{Name: "length", Pattern: `\d+`},
+			{Name: "symbols", Pattern: `[` + regexp.QuoteMeta(Symbols+extraSymbols) + `]+`},
+			{Name: "num_digits", Pattern: `\d{1,2}`},
+			{Name: "num_symbols", Pattern: `\d{1,2}`},
+			{Name: "encoding"},
+		},
+		Rotatable: true,
+	}, generatePasswordValue),
+	NewGenerator("uuid", Schema{
+		Arguments: []Argument{
+			{Name: "encoding"},
+		},
+		Rotatable: true,
+	}, generateUUIDValue),
+	NewGenerator("mac", Schema{}, func(ctx context.Context, args Arguments) (string, error) {
+		return generateMAC(EnvironmentFromContext(ctx).Reader())
+	}),
+	NewGenerator("ula", Schema{
+		Arguments: []Argument{
+			{Name: "prefix_length", Pattern: `\d{2}`},
+		},
+	}, func(ctx context.Context, args Arguments) (string, error) {
+		return generateULAPrefix(EnvironmentFromContext(ctx).Reader(), args.Int("prefix_length", 48))
+	}),
+	NewGenerator("port", Schema{
+		Arguments: []Argument{
+			{Name: "min", Pattern: `\d{1,5}`},
+			{Name: "max", Pattern: `\d{1,5}`},
+		},
+	}, func(ctx context.Context, args Arguments) (string, error) {
+		return generatePort(EnvironmentFromContext(ctx).Reader(), args.Int("min", 49152), args.Int("max", 65535))
+	}),
+	NewGenerator("choice", Schema{
+		Arguments: []Argument{
+			{Name: "values"},
+			{Name: "config_map", Pattern: `[a-z0-9.-]+`},
+			{Name: "key", Pattern: `[A-Za-z0-9._-]+`},
+			{Name: "count", Pattern: `\d{1,3}`},
+			{Name: "separator", Pattern: `.*`},
+		},
+	}, generateChoiceValue),
+	NewGenerator("sequence", Schema{
+		Arguments: []Argument{
+			// no dots, since they separate the namespace from the name of counters of namespace scope
+			{Name: "name", Pattern: `[A-Za-z0-9_-]+`, Required: true},
+			{Name: "start", Pattern: `\d{1,18}`},
+			{Name: "scope", Pattern: `cluster|namespace`},
+		},
+	}, generateSequenceValue),
+	NewGenerator("timestamp", Schema{
+		Arguments: []Argument{
+			{Name: "offset", Validate: ValidateDuration},
+			{Name: "format", Pattern: `rfc3339|unix|unix_milli`},
+			{Name: "layout"},
+		},
+	}, generateTimestampValue),
+	NewGenerator("username", Schema{
+		Arguments: []Argument{
+			{Name: "style", Pattern: `random|words`},
+			{Name: "prefix", Pattern: `[a-z0-9][a-z0-9-]*`},
+			{Name: "length", Pattern: `\d{1,3}`},
+			{Name: "max_length", Pattern: `\d{1,3}`},
+			{Name: "alphabet", Pattern: `lower|lower_digits`},
+			{Name: "separator", Pattern: `[_.-]?`},
+			{Name: "digits", Pattern: `\d`},
+		},
+	}, generateUsernameValue),
+	NewGenerator("preset", Schema{
+		Arguments: []Argument{
+			{Name: "name", Pattern: `[a-z0-9-]+`, Required: true},
+		},
+		Positional: "name",
+		Rotatable:  true,
+	}, func(ctx context.Context, args Arguments) (string, error) {
+		preset, ok := presets[args["name"]]
+		if !ok {
+			return "", fmt.Errorf("unsupported preset: %s", args["name"])
+		}
+		return preset(EnvironmentFromContext(ctx).Reader())
+	}),
+	NewBundleGenerator("mtls", Schema{
+		Arguments: []Argument{
+			{Name: "ca_common_name"},
+			{Name: "common_name"},
+			{Name: "client_common_name"},
+			{Name: "dns_names", Pattern: `[A-Za-z0-9.*-]+(?:,[A-Za-z0-9.*-]+)*`},
+			{Name: "ip_addresses", Validate: validateIPAddresses},
+			{Name: "key_algorithm", Pattern: `ecdsa|rsa|ed25519`},
+			{Name: "validity", Validate: ValidatePositiveDuration},
+			{Name: "key_prefix", Pattern: `[A-Za-z0-9._-]+`},
+		},
+		Rotatable: true,
+	}, func(args Arguments) []string {
+		return prefixKeys(args.String("key_prefix", ""), mtlsKeys)
+	}, generateMTLSValues),
+	NewBundleGenerator("ssh", Schema{
+		Arguments: []Argument{
+			{Name: "key_algorithm", Pattern: `ed25519|ecdsa|rsa`},
+			{Name: "comment"},
+			{Name: "ca_secret", Pattern: `[a-z0-9.-]+`},
+			{Name: "ca_key", Pattern: `[A-Za-z0-9._-]+`},
+			{Name: "cert_type", Pattern: `user|host`},
+			{Name: "key_id"},
+			{Name: "principals", Pattern: `[^,]+(?:,[^,]+)*`},
+			{Name: "validity", Validate: ValidatePositiveDuration},
+			{Name: "extensions", Pattern: `[A-Za-z0-9@.-]*(?:,[A-Za-z0-9@.-]+)*`},
+		},
+		Rotatable: true,
+	}, func(args Arguments) []string {
+		keys := []string{SSHKeyPrivateKey, SSHKeyPublicKey}
+		if args.Has("ca_secret") {
+			keys = append(keys, SSHKeyCertificate)
+		}
+		return keys
+	}, generateSSHValues),
+	NewBundleGenerator("s3", Schema{
+		Arguments: []Argument{
+			{Name: "key_prefix", Pattern: `[A-Za-z0-9._-]+`},
+		},
+		Rotatable: true,
+	}, func(args Arguments) []string {
+		return prefixKeys(args.String("key_prefix", ""), s3Keys)
+	}, generateS3Values),
+}
+
+func init() {
+	for _, generator := range builtinGenerators {
+		if err := DefaultRegistry.Register(generator); err != nil {
+			panic(err)
+		}
+	}
+}
+
+func generatePasswordValue(ctx context.Context, args Arguments) (string, error) {
+	length := args.Int("length", 32)
+	symbols := Symbols
+	if args.Has("symbols") {
+		symbols = normalizeSymbols(args["symbols"])
+	}
+	value, err := generatePassword(EnvironmentFromContext(ctx).Reader(), length, args.Int("num_digits", length/4), args.Int("num_symbols", length/4), symbols)
+	if err != nil {
+		return "", err
+	}
+	if !args.Has("encoding") {
+		return value, nil
+	}
+	return encode(args["encoding"], []byte(value))
+}
+
+func generateUUIDValue(ctx context.Context, args Arguments) (string, error) {
+	generatedUuid, err := uuid.NewRandomFromReader(EnvironmentFromContext(ctx).Reader())
+	if err != nil {
+		return "", err
+	}
+	if !args.Has("encoding") {
+		return generatedUuid.String(), nil
+	}
+	return encode(args["encoding"], generatedUuid[:])
+}
+
+func generateChoiceValue(ctx context.Context, args Arguments) (string, error) {
+	values := args.List("values")
+	if (values == nil) == !args.Has("config_map") {
+		return "", fmt.Errorf("invalid choice generator arguments: exactly one of values and config_map must be specified")
+	}
+	if args.Has("config_map") {
+		if !args.Has("key") {
+			return "", fmt.Errorf("invalid choice generator arguments: key must be specified along with config_map")
+		}
+		env := EnvironmentFromContext(ctx)
+		var err error
+		values, err = getConfigMapLines(ctx, env.Client, env.Namespace, args["config_map"], args["key"])
+		if err != nil {
+			return "", err
+		}
+	}
+	choices, err := randomChoice(EnvironmentFromContext(ctx).Reader(), values, args.Int("count", 1))
+	if err != nil {
+		return "", err
+	}
+	return strings.Join(choices, args.String("separator", ",")), nil
+}
+
+func generateSequenceValue(ctx context.Context, args Arguments) (string, error) {
+	start, _ := strconv.ParseInt(args.String("start", "1"), 10, 64)
+	env := EnvironmentFromContext(ctx)
+	namespace := env.SequenceNamespace
+	if namespace == "" {
+		namespace = env.Namespace
+	}
+	// counters of namespace scope are qualified with the namespace of the secret; since neither namespace names nor counter names
+	// contain dots, they cannot collide with each other, or with counters of cluster scope
+	name := args["name"]
+	if args["scope"] == "namespace" {
+		name = env.Namespace + "." + name
+	}
+	value, err := nextSequenceValue(ctx, env.Client, namespace, name, start, env.DryRun)
+	if err != nil {
+		return "", err
+	}
+	return strconv.FormatInt(value, 10), nil
+}
+
+func generateTimestampValue(ctx context.Context, args Arguments) (string, error) {
+	timestamp := time.Now().UTC().Add(args.Duration("offset", 0))
+	switch {
+	case args.Has("layout"):
+		return timestamp.Format(args["layout"]), nil
+	case args["format"] == "unix":
+		return strconv.FormatInt(timestamp.Unix(), 10), nil
+	case args["format"] == "unix_milli":
+		return strconv.FormatInt(timestamp.UnixMilli(), 10), nil
+	default:
+		return timestamp.Format(time.RFC3339), nil
+	}
+}
+
+func generateUsernameValue(ctx context.Context, args Arguments) (string, error) {
+	options := &usernameOptions{
+		style:     args.String("style", "random"),
+		prefix:    args.String("prefix", ""),
+		length:    args.Int("length", 16),
+		maxLength: args.Int("max_length", 63),
+		alphabet:  alphabetLower + alphabetDigits,
+		separator: args.String("separator", "-"),
+		digits:    args.Int("digits", 0),
+	}
+	if args["alphabet"] == "lower" {
+		options.alphabet = alphabetLower
+	}
+	return generateUsername(EnvironmentFromContext(ctx).Reader(), options)
+}
+
+func validateIPAddresses(value string) error {
+	for _, s := range strings.Split(value, ",") {
+		if net.ParseIP(s) == nil {
+			return fmt.Errorf("invalid ip address %s", s)
+		}
+	}
+	return nil
+}
+
+func generateMTLSValues(ctx context.Context, args Arguments) (Values, error) {
+	options := &mtlsOptions{
+		caCommonName:     args.String("ca_common_name", "secret-generator-ca"),
+		commonName:       args.String("common_name", ""),
+		clientCommonName: args.String("client_common_name", "client"),
+		dnsNames:         args.List("dns_names"),
+		keyAlgorithm:     args.String("key_algorithm", "ecdsa"),
+		validity:         args.Duration("validity", 365*24*time.Hour),
+	}
+	for _, s := range args.List("ip_addresses") {
+		options.ipAddresses = append(options.ipAddresses, net.ParseIP(s))
+	}
+	if options.commonName == "" {
+		if len(options.dnsNames) > 0 {
+			options.commonName = options.dnsNames[0]
+		} else {
+			options.commonName = "server"
+		}
+	}
+	values, err := generateMTLSBundle(options)
+	if err != nil {
+		return nil, err
+	}
+	return prefixValues(args.String("key_prefix", ""), values), nil
+}
+
+func generateS3Values(ctx context.Context, args Arguments) (Values, error) {
+	values, err := generateS3Credentials(EnvironmentFromContext(ctx).Reader())
+	if err != nil {
+		return nil, err
+	}
+	return prefixValues(args.String("key_prefix", ""), values), nil
+}
+
+// return keys, with prefix prepended to each of them
+func prefixKeys(prefix string, keys []string) []string {
+	prefixedKeys := make([]string, len(keys))
+	for i, k := range keys {
+		prefixedKeys[i] = prefix + k
+	}
+	return prefixedKeys
+}
+
+// return values, with prefix prepended to each of their keys
+func prefixValues(prefix string, values Values) Values {
+	if prefix == "" {
+		return values
+	}
+	prefixedValues := make(Values, len(values))
+	for k, v := range values {
+		prefixedValues[prefix+k] = v
+	}
+	return prefixedValues
+}
+
+func generateSSHValues(ctx context.Context, args Arguments) (Values, error) {
+	options := &sshOptions{
+		keyAlgorithm: args.String("key_algorithm", "ed25519"),
+		comment:      args.String("comment", ""),
+		certType:     sshCertTypeUser,
+		keyId:        args.String("key_id", "secret-generator"),
+		principals:   args.List("principals"),
+		validity:     args.Duration("validity", 365*24*time.Hour),
+	}
+	if args["cert_type"] == "host" {
+		options.certType = sshCertTypeHost
+	}
+	if args.Has("ca_secret") {
+		// ssh clients accept host certificates without principals for any host
+		if options.certType == sshCertTypeHost && len(options.principals) == 0 {
+			return nil, &ArgumentError{Generator: "ssh", Reason: "principals must be specified for host certificates"}
+		}
+		env := EnvironmentFromContext(ctx)
+		ca, err := getSSHCA(ctx, env.Client, env.User, env.Namespace, args["ca_secret"], args.String("ca_key", SSHKeyPrivateKey))
+		if err != nil {
+			return nil, err
+		}
+		options.ca = ca
+		if args.Has("extensions") {
+			options.extensions = append([]string{}, args.List("extensions")...)
+		} else if options.certType == sshCertTypeUser {
+			options.extensions = sshDefaultUserExtensions
+		}
+	}
+	return generateSSHBundle(options)
+}
+
+func encode(encoding string, value []byte) (string, error) {
+	var encodedValue string
+	var err error
+
+	switch encoding {
+	case "base32":
+		encodedValue = base32.StdEncoding.EncodeToString(value)
+	case "base64":
+		encodedValue = base64.StdEncoding.EncodeToString(value)
+	case "base64_url":
+		encodedValue = base64.URLEncoding.EncodeToString(value)
+	case "base64_raw":
+		encodedValue = base64.RawStdEncoding.EncodeToString(value)
+	case "base64_raw_url":
+		encodedValue = base64.RawURLEncoding.EncodeToString(value)
+	case "hex":
+		encodedValue = hex.EncodeToString(value)
+	default:
+		err = fmt.Errorf("unsupported encoding %s", encoding)
+	}
+
+	return encodedValue, err
+}
+
+func generatePassword(random io.Reader, length int, numDigits int, numSymbols int, symbols string) (string, error) {
+	input := &password.GeneratorInput{Symbols: symbols, Reader: random}
+	generator, err := password.NewGenerator(input)
+	if err != nil {
+		return "", err
+	}
+	return generator.Generate(length, numDigits, numSymbols, false, true)
+}
@@ -0,0 +1,170 @@
+/*
+SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company and secret-generator contributors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package generator
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"net"
+	"time"
+)
+
+const (
+	MTLSKeyCACert     = "ca.crt"
+	MTLSKeyServerCert = "server.crt"
+	MTLSKeyServerKey  = "server.key"
+	MTLSKeyClientCert = "client.crt"
+	MTLSKeyClientKey  = "client.key"
+)
+
+var mtlsKeys = []string{MTLSKeyCACert, MTLSKeyServerCert, MTLSKeyServerKey, MTLSKeyClientCert, MTLSKeyClientKey}
+
+type mtlsOptions struct {
+	caCommonName     string
+	commonName       string
+	clientCommonName string
+	dnsNames         []string
+	ipAddresses      []net.IP
+	keyAlgorithm     string
+	validity         time.Duration
+}
+
+// generate a private CA, and a server and a client certificate signed by that CA;
+// the CA private key is discarded after signing.
+func generateMTLSBundle(options *mtlsOptions) (map[string]string, error) {
+	notBefore := time.Now().Add(-5 * time.Minute)
+	notAfter := notBefore.Add(options.validity)
+
+	caKey, err := generatePrivateKey(options.keyAlgorithm)
+	if err != nil {
+		return nil, err
+	}
+	caTemplate := &x509.Certificate{
+		Subject:               pkix.Name{CommonName: options.caCommonName},
+		NotBefore:             notBefore,
+		NotAfter:              notAfter,
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageCRLSign | x509.KeyUsageDigitalSignature,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+		MaxPathLenZero:        true,
+	}
+	caCert, caCertPEM, err := signCertificate(caTemplate, caTemplate, caKey.Public(), caKey)
+	if err != nil {
+		return nil, err
+	}
+
+	dnsNames := options.dnsNames
+	if len(dnsNames) == 0 && len(options.ipAddresses) == 0 {
+		dnsNames = []string{options.commonName}
+	}
+	serverKey, err := generatePrivateKey(options.keyAlgorithm)
+	if err != nil {
+		return nil, err
+	}
+	serverTemplate := &x509.Certificate{
+		Subject:     pkix.Name{CommonName: options.commonName},
+		DNSNames:    dnsNames,
+		IPAddresses: options.ipAddresses,
+		NotBefore:   notBefore,
+		NotAfter:    notAfter,
+		KeyUsage:    leafKeyUsage(serverKey),
+		ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	_, serverCertPEM, err := signCertificate(serverTemplate, caCert, serverKey.Public(), caKey)
+	if err != nil {
+		return nil, err
+	}
+	serverKeyPEM, err := encodePrivateKey(serverKey)
+	if err != nil {
+		return nil, err
+	}
+
+	clientKey, err := generatePrivateKey(options.keyAlgorithm)
+	if err != nil {
+		return nil, err
+	}
+	clientTemplate := &x509.Certificate{
+		Subject:     pkix.Name{CommonName: options.clientCommonName},
+		NotBefore:   notBefore,
+		NotAfter:    notAfter,
+		KeyUsage:    leafKeyUsage(clientKey),
+		ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	_, clientCertPEM, err := signCertificate(clientTemplate, caCert, clientKey.Public(), caKey)
+	if err != nil {
+		return nil, err
+	}
+	clientKeyPEM, err := encodePrivateKey(clientKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return map[string]string{
+		MTLSKeyCACert:     caCertPEM,
+		MTLSKeyServerCert: serverCertPEM,
+		MTLSKeyServerKey:  serverKeyPEM,
+		MTLSKeyClientCert: clientCertPEM,
+		MTLSKeyClientKey:  clientKeyPEM,
+	}, nil
+}
+
+func generatePrivateKey(algorithm string) (crypto.Signer, error) {
+	switch algorithm {
+	case "ecdsa":
+		return ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	case "rsa":
+		return rsa.GenerateKey(rand.Reader, 2048)
+	case "ed25519":
+		_, key, err := ed25519.GenerateKey(rand.Reader)
+		return key, err
+	default:
+		return nil, fmt.Errorf("unsupported key algorithm %s", algorithm)
+	}
+}
+
+// return the key usage of a leaf certificate for key; as in crypto/tls, key encipherment only applies to RSA keys
+// (which may be used for RSA key exchange), whereas ECDSA and ed25519 keys only sign
+func leafKeyUsage(key crypto.Signer) x509.KeyUsage {
+	usage := x509.KeyUsageDigitalSignature
+	if _, ok := key.Public().(*rsa.PublicKey); ok {
+		usage |= x509.KeyUsageKeyEncipherment
+	}
+	return usage
+}
+
+func signCertificate(template *x509.Certificate, parent *x509.Certificate, publicKey crypto.PublicKey, signer crypto.Signer) (*x509.Certificate, string, error) {
+	serialNumber, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return nil, "", err
+	}
+	template.SerialNumber = serialNumber
+	der, err := x509.CreateCertificate(rand.Reader, template, parent, publicKey, signer)
+	if err != nil {
+		return nil, "", err
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		return nil, "", err
+	}
+	return cert, string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})), nil
+}
+
+func encodePrivateKey(key crypto.Signer) (string, error) {
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		return "", err
+	}
+	return string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})), nil
+}
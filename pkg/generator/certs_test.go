@@ -0,0 +1,82 @@
+/*
+SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company and secret-generator contributors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package generator
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"net"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestGenerateMTLSBundle(t *testing.T) {
+	for _, keyAlgorithm := range []string{"ecdsa", "rsa", "ed25519"} {
+		values, err := generateMTLSBundle(&mtlsOptions{
+			caCommonName:     "test-ca",
+			commonName:       "db",
+			clientCommonName: "postgres",
+			dnsNames:         []string{"db", "db.testing.svc"},
+			ipAddresses:      []net.IP{net.ParseIP("10.0.0.1")},
+			keyAlgorithm:     keyAlgorithm,
+			validity:         24 * time.Hour,
+		})
+		if err != nil {
+			t.Fatalf("generateMTLSBundle: got error: %s", err)
+		}
+
+		roots := x509.NewCertPool()
+		if !roots.AppendCertsFromPEM([]byte(values[MTLSKeyCACert])) {
+			t.Fatalf("generateMTLSBundle: got invalid ca certificate")
+		}
+
+		serverCert := parseCertificate(t, values[MTLSKeyServerCert])
+		if _, err := serverCert.Verify(x509.VerifyOptions{Roots: roots, DNSName: "db.testing.svc", KeyUsages: []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth}}); err != nil {
+			t.Errorf("generateMTLSBundle: got invalid server certificate; error: %s", err)
+		}
+		if !slices.ContainsFunc(serverCert.IPAddresses, func(ip net.IP) bool { return ip.Equal(net.ParseIP("10.0.0.1")) }) {
+			t.Errorf("generateMTLSBundle: got server certificate without ip address")
+		}
+		expectedKeyUsage := x509.KeyUsageDigitalSignature
+		if keyAlgorithm == "rsa" {
+			expectedKeyUsage |= x509.KeyUsageKeyEncipherment
+		}
+		if serverCert.KeyUsage != expectedKeyUsage {
+			t.Errorf("generateMTLSBundle: got invalid key usage of %s server certificate: %b", keyAlgorithm, serverCert.KeyUsage)
+		}
+		if _, err := tls.X509KeyPair([]byte(values[MTLSKeyServerCert]), []byte(values[MTLSKeyServerKey])); err != nil {
+			t.Errorf("generateMTLSBundle: got non-matching server key pair; error: %s", err)
+		}
+
+		clientCert := parseCertificate(t, values[MTLSKeyClientCert])
+		if _, err := clientCert.Verify(x509.VerifyOptions{Roots: roots, KeyUsages: []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth}}); err != nil {
+			t.Errorf("generateMTLSBundle: got invalid client certificate; error: %s", err)
+		}
+		if clientCert.KeyUsage != expectedKeyUsage {
+			t.Errorf("generateMTLSBundle: got invalid key usage of %s client certificate: %b", keyAlgorithm, clientCert.KeyUsage)
+		}
+		if clientCert.Subject.CommonName != "postgres" {
+			t.Errorf("generateMTLSBundle: got invalid client common name: %s", clientCert.Subject.CommonName)
+		}
+		if _, err := tls.X509KeyPair([]byte(values[MTLSKeyClientCert]), []byte(values[MTLSKeyClientKey])); err != nil {
+			t.Errorf("generateMTLSBundle: got non-matching client key pair; error: %s", err)
+		}
+	}
+}
+
+func parseCertificate(t *testing.T, data string) *x509.Certificate {
+	block, _ := pem.Decode([]byte(data))
+	if block == nil {
+		t.Fatalf("parseCertificate: got invalid pem data")
+	}
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		t.Fatalf("parseCertificate: got error: %s", err)
+	}
+	return cert
+}
@@ -0,0 +1,100 @@
+/*
+SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company and secret-generator contributors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package generator
+
+import (
+	"context"
+	"crypto"
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	authenticationv1 "k8s.io/api/authentication/v1"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// read the private key of an ssh certificate authority from a secret; since the secret is read with the webhook's permissions,
+// the requester must be allowed to get the secret as well (otherwise everybody who may create secrets could obtain certificates)
+func getSSHCA(ctx context.Context, client kubernetes.Interface, user *authenticationv1.UserInfo, namespace string, name string, key string) (crypto.Signer, error) {
+	if client == nil {
+		return nil, fmt.Errorf("unable to read ssh ca secret %s: no kubernetes client configured", name)
+	}
+	if err := checkSecretAccess(ctx, client, user, namespace, name); err != nil {
+		return nil, errors.Wrapf(err, "error reading ssh ca secret %s", name)
+	}
+	secret, err := client.CoreV1().Secrets(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, errors.Wrapf(err, "error reading ssh ca secret %s", name)
+	}
+	data, ok := secret.Data[key]
+	if !ok {
+		return nil, fmt.Errorf("ssh ca secret %s has no key %s", name, key)
+	}
+	ca, err := parseSSHPrivateKey(data)
+	if err != nil {
+		return nil, errors.Wrapf(err, "error parsing private key from ssh ca secret %s", name)
+	}
+	return ca, nil
+}
+
+// check if user may get the given secret, by a subject access review
+func checkSecretAccess(ctx context.Context, client kubernetes.Interface, user *authenticationv1.UserInfo, namespace string, name string) error {
+	if user == nil {
+		return fmt.Errorf("requester is unknown, and cannot be authorized")
+	}
+	extra := make(map[string]authorizationv1.ExtraValue)
+	for k, v := range user.Extra {
+		extra[k] = authorizationv1.ExtraValue(v)
+	}
+	review := &authorizationv1.SubjectAccessReview{
+		Spec: authorizationv1.SubjectAccessReviewSpec{
+			ResourceAttributes: &authorizationv1.ResourceAttributes{
+				Namespace: namespace,
+				Verb:      "get",
+				Resource:  "secrets",
+				Name:      name,
+			},
+			User:   user.Username,
+			UID:    user.UID,
+			Groups: user.Groups,
+			Extra:  extra,
+		},
+	}
+	review, err := client.AuthorizationV1().SubjectAccessReviews().Create(ctx, review, metav1.CreateOptions{})
+	if err != nil {
+		return errors.Wrap(err, "error checking access of requester")
+	}
+	if !review.Status.Allowed {
+		return fmt.Errorf("user %s is not allowed to get secret %s", user.Username, name)
+	}
+	return nil
+}
+
+// return the non-empty lines of a config map value, ignoring comment lines (starting with #)
+func getConfigMapLines(ctx context.Context, client kubernetes.Interface, namespace string, name string, key string) ([]string, error) {
+	if client == nil {
+		return nil, fmt.Errorf("unable to read config map %s: no kubernetes client configured", name)
+	}
+	configMap, err := client.CoreV1().ConfigMaps(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, errors.Wrapf(err, "error reading config map %s", name)
+	}
+	data, ok := configMap.Data[key]
+	if !ok {
+		return nil, fmt.Errorf("config map %s has no key %s", name, key)
+	}
+	var lines []string
+	for _, line := range strings.Split(data, "\n") {
+		line = strings.TrimSpace(line)
+		if line != "" && !strings.HasPrefix(line, "#") {
+			lines = append(lines, line)
+		}
+	}
+	return lines, nil
+}
@@ -0,0 +1,357 @@
+/*
+SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company and secret-generator contributors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package generator implements the value generators of the secret-generator webhook, such that other programs
+// can produce exactly the same value formats.
+package generator
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+	"io"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	authenticationv1 "k8s.io/api/authentication/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// Generator produces values for secret keys; a generator is selected by the type part of a placeholder
+// such as %generate:<type>:<arg>=<value>;...
+type Generator interface {
+	// Name returns the generator type, as used in placeholders.
+	Name() string
+	// Schema describes the arguments accepted by the generator; arguments are validated against the schema before Generate() is called.
+	Schema() Schema
+	// Generate produces the value(s); regular generators return exactly one value, with the empty key, which replaces
+	// the placeholder; bundle generators (see BundleGenerator) return one value per key listed by Keys().
+	Generate(ctx context.Context, args Arguments) (Values, error)
+}
+
+// BundleGenerator is a generator producing multiple secret keys at once; the key holding the placeholder is removed,
+// and the keys returned by Keys() are added to the secret.
+type BundleGenerator interface {
+	Generator
+	// Keys returns the secret keys produced by Generate() for the given arguments.
+	Keys(args Arguments) []string
+}
+
+// Values produced by a generator, by secret key.
+type Values map[string]string
+
+// Schema describes the arguments accepted by a generator.
+type Schema struct {
+	// Arguments lists the accepted named arguments.
+	Arguments []Argument
+	// Positional optionally names an argument which may be specified without name (e.g. %generate:preset:django-secret-key).
+	Positional string
+	// AdditionalArguments allows arguments not listed in Arguments; their values are not validated.
+	AdditionalArguments bool
+	// Rotatable marks generators producing credentials (such as passwords or keys), which are rotated if all keys of a secret are rotated
+	// (or exceed a default maximum age); values of other generators (such as identifiers or port numbers) are only rotated if requested explicitly.
+	Rotatable bool
+}
+
+// Argument describes a generator argument.
+type Argument struct {
+	// Name of the argument.
+	Name string
+	// Pattern is a regular expression the (complete) argument value has to match; if empty, any non-empty value is accepted.
+	Pattern string
+	// Validate optionally performs additional checks on the argument value.
+	Validate func(value string) error
+	// Required arguments must be specified.
+	Required bool
+	// Deprecated optionally explains that the argument should no longer be used (and what to use instead).
+	Deprecated string
+}
+
+// Arguments passed to a generator, by name; values were validated according to the generator's schema.
+type Arguments map[string]string
+
+// Has checks if an argument was specified.
+func (a Arguments) Has(name string) bool {
+	_, ok := a[name]
+	return ok
+}
+
+// String returns the value of an argument, or the given default, if the argument was not specified.
+func (a Arguments) String(name string, defaultValue string) string {
+	if v, ok := a[name]; ok {
+		return v
+	}
+	return defaultValue
+}
+
+// Int returns the value of an integer argument, or the given default, if the argument was not specified.
+func (a Arguments) Int(name string, defaultValue int) int {
+	if v, ok := a[name]; ok {
+		if i, err := strconv.Atoi(v); err == nil {
+			return i
+		}
+	}
+	return defaultValue
+}
+
+// Duration returns the value of a duration argument (see ValidateDuration), or the given default, if the argument was not specified.
+func (a Arguments) Duration(name string, defaultValue time.Duration) time.Duration {
+	if v, ok := a[name]; ok {
+		if d, err := ParseDuration(v); err == nil {
+			return d
+		}
+	}
+	return defaultValue
+}
+
+// List returns the value of a comma-separated list argument, or nil, if the argument was not specified.
+func (a Arguments) List(name string) []string {
+	if v, ok := a[name]; ok && v != "" {
+		return strings.Split(v, ",")
+	}
+	return nil
+}
+
+// ValidateDuration checks that a value is a Go duration or a number of days (such as 90d).
+func ValidateDuration(value string) error {
+	_, err := ParseDuration(value)
+	return err
+}
+
+// ValidatePositiveDuration checks that a value is a positive Go duration or number of days (such as 90d).
+func ValidatePositiveDuration(value string) error {
+	d, err := ParseDuration(value)
+	if err == nil && d <= 0 {
+		err = fmt.Errorf("duration must be positive")
+	}
+	return err
+}
+
+// ParseArguments parses and validates the argument part of a placeholder (<arg>=<value>;<arg>=<value>;...) according to the schema of a generator;
+// if the arguments are syntactically invalid, a *SyntaxError is returned; if they do not match the schema, an *ArgumentError is returned.
+func ParseArguments(generator Generator, s string) (Arguments, error) {
+	list, err := parseArgumentList(s, 0)
+	if err != nil {
+		return nil, err
+	}
+	return bindArguments(generator, list)
+}
+
+// validate parsed arguments against the schema of a generator
+func bindArguments(generator Generator, list []SpecArgument) (Arguments, error) {
+	schema := generator.Schema()
+	args := make(Arguments)
+	for _, arg := range list {
+		name := arg.Name
+		if name == "" {
+			if schema.Positional == "" {
+				return nil, &ArgumentError{Generator: generator.Name(), Argument: arg.String(), Position: arg.Position}
+			}
+			name = schema.Positional
+		}
+		if _, ok := args[name]; ok {
+			return nil, &ArgumentError{Generator: generator.Name(), Argument: arg.String(), Position: arg.Position, Reason: "specified more than once"}
+		}
+		i := slices.IndexFunc(schema.Arguments, func(a Argument) bool { return a.Name == name })
+		if i < 0 {
+			if schema.AdditionalArguments && arg.Name != "" {
+				args[name] = arg.Value
+				continue
+			}
+			return nil, &ArgumentError{Generator: generator.Name(), Argument: arg.String(), Position: arg.Position}
+		}
+		spec := schema.Arguments[i]
+		pattern := ".+"
+		if spec.Pattern != "" {
+			pattern = spec.Pattern
+		}
+		re, err := regexp.Compile(`^(?:` + pattern + `)$`)
+		if err != nil {
+			return nil, fmt.Errorf("invalid pattern for %s generator argument %s: %s", generator.Name(), name, err)
+		}
+		if !re.MatchString(arg.Value) {
+			return nil, &ArgumentError{Generator: generator.Name(), Argument: arg.String(), Position: arg.Position}
+		}
+		if spec.Validate != nil {
+			if err := spec.Validate(arg.Value); err != nil {
+				return nil, &ArgumentError{Generator: generator.Name(), Argument: arg.String(), Position: arg.Position, Reason: err.Error()}
+			}
+		}
+		args[name] = arg.Value
+	}
+	for _, spec := range schema.Arguments {
+		if _, ok := args[spec.Name]; spec.Required && !ok {
+			return nil, &ArgumentError{Generator: generator.Name(), Reason: spec.Name + " must be specified"}
+		}
+	}
+	return args, nil
+}
+
+// NewGenerator creates a regular generator from a function returning a single value.
+func NewGenerator(name string, schema Schema, generate func(ctx context.Context, args Arguments) (string, error)) Generator {
+	return &funcGenerator{
+		name:   name,
+		schema: schema,
+		generate: func(ctx context.Context, args Arguments) (Values, error) {
+			value, err := generate(ctx, args)
+			if err != nil {
+				return nil, err
+			}
+			return Values{"": value}, nil
+		},
+	}
+}
+
+// NewBundleGenerator creates a bundle generator from a function returning multiple values, and a function returning the according keys.
+func NewBundleGenerator(name string, schema Schema, keys func(args Arguments) []string, generate func(ctx context.Context, args Arguments) (Values, error)) BundleGenerator {
+	return &funcBundleGenerator{
+		funcGenerator: funcGenerator{
+			name:     name,
+			schema:   schema,
+			generate: generate,
+		},
+		keys: keys,
+	}
+}
+
+type funcGenerator struct {
+	name     string
+	schema   Schema
+	generate func(ctx context.Context, args Arguments) (Values, error)
+}
+
+func (g *funcGenerator) Name() string {
+	return g.name
+}
+
+func (g *funcGenerator) Schema() Schema {
+	return g.schema
+}
+
+func (g *funcGenerator) Generate(ctx context.Context, args Arguments) (Values, error) {
+	return g.generate(ctx, args)
+}
+
+type funcBundleGenerator struct {
+	funcGenerator
+	keys func(args Arguments) []string
+}
+
+func (g *funcBundleGenerator) Keys(args Arguments) []string {
+	return g.keys(args)
+}
+
+// Registry holds generators by name.
+type Registry struct {
+	mutex      sync.RWMutex
+	generators map[string]Generator
+}
+
+// NewRegistry creates an empty registry.
+func NewRegistry() *Registry {
+	return &Registry{generators: make(map[string]Generator)}
+}
+
+// Register adds a generator to the registry; registering two generators with the same name is an error.
+func (r *Registry) Register(generator Generator) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	name := generator.Name()
+	if !regexp.MustCompile(`^[a-z0-9_-]+$`).MatchString(name) {
+		return fmt.Errorf("invalid generator name: %s", name)
+	}
+	if _, ok := r.generators[name]; ok {
+		return fmt.Errorf("generator %s already registered", name)
+	}
+	r.generators[name] = generator
+	return nil
+}
+
+// Get returns the generator with the given name.
+func (r *Registry) Get(name string) (Generator, bool) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	generator, ok := r.generators[name]
+	return generator, ok
+}
+
+// Names returns the (sorted) names of all registered generators.
+func (r *Registry) Names() []string {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	var names []string
+	for name := range r.generators {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// Restrict returns a new registry containing only the generators with the given names (which exist in this registry).
+func (r *Registry) Restrict(names ...string) *Registry {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	registry := NewRegistry()
+	for _, name := range names {
+		if generator, ok := r.generators[name]; ok {
+			registry.generators[name] = generator
+		}
+	}
+	return registry
+}
+
+// DefaultRegistry contains the built-in generators; it is used by the package-level generation functions, and by default by the webhook.
+var DefaultRegistry = NewRegistry()
+
+// RegisterGenerator adds a generator to the default registry; it is meant to be called during program initialization.
+func RegisterGenerator(generator Generator) error {
+	return DefaultRegistry.Register(generator)
+}
+
+// Environment provides generators with access to the cluster, and with the source of randomness to be used.
+type Environment struct {
+	// Client may be nil; generators needing cluster access fail in that case.
+	Client kubernetes.Interface
+	// Namespace of the secret being mutated.
+	Namespace string
+	// User is the requester of the admission request, if known; generators using cluster objects with the webhook's permissions
+	// (such as the ssh certificate authority) check the requester's permissions on them.
+	User *authenticationv1.UserInfo
+	// SequenceNamespace is the namespace of the config map holding the counters of the sequence generator; if empty, Namespace is used.
+	SequenceNamespace string
+	// DryRun indicates that the admission request is a dry run; generators must not persist any state then (such as sequence counters),
+	// but return a preview of the value they would generate.
+	DryRun bool
+	// Random is the source of randomness for generated values; if nil, crypto/rand.Reader is used.
+	// Note that private keys (of the mtls and ssh generators) are always generated from crypto/rand.
+	Random io.Reader
+}
+
+// Reader returns the source of randomness to be used by generators.
+func (e *Environment) Reader() io.Reader {
+	if e.Random == nil {
+		return rand.Reader
+	}
+	return e.Random
+}
+
+type environmentContextKey struct{}
+
+// WithEnvironment returns a copy of ctx carrying the given environment.
+func WithEnvironment(ctx context.Context, env *Environment) context.Context {
+	return context.WithValue(ctx, environmentContextKey{}, env)
+}
+
+// EnvironmentFromContext returns the environment carried by ctx; if there is none, an empty environment is returned.
+func EnvironmentFromContext(ctx context.Context) *Environment {
+	if env, ok := ctx.Value(environmentContextKey{}).(*Environment); ok {
+		return env
+	}
+	return &Environment{}
+}
@@ -0,0 +1,117 @@
+/*
+SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company and secret-generator contributors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package generator
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseArguments(t *testing.T) {
+	generator := NewGenerator("test", Schema{
+		Arguments: []Argument{
+			{Name: "name", Pattern: `[a-z]+`, Required: true},
+			{Name: "count", Pattern: `\d+`},
+			{Name: "validity", Validate: ValidatePositiveDuration},
+			{Name: "list", Pattern: `[a-z]+(?:,[a-z]+)*`},
+		},
+		Positional: "name",
+	}, nil)
+
+	args, err := ParseArguments(generator, "name=foo;count=7;validity=2d;list=a,b")
+	if err != nil {
+		t.Fatalf("got error: %s", err)
+	}
+	if args.String("name", "") != "foo" || args.Int("count", 0) != 7 || args.Duration("validity", 0) != 48*time.Hour || strings.Join(args.List("list"), "|") != "a|b" {
+		t.Errorf("got invalid arguments: %v", args)
+	}
+	if args.Has("other") || args.Int("other", 3) != 3 || args.List("other") != nil {
+		t.Errorf("got unexpected defaults for unspecified argument: %v", args)
+	}
+
+	args, err = ParseArguments(generator, "bar")
+	if err != nil {
+		t.Fatalf("got error: %s", err)
+	}
+	if args["name"] != "bar" {
+		t.Errorf("got invalid positional argument: %v", args)
+	}
+
+	for _, s := range []string{
+		"",
+		"count=7",
+		"name=Foo",
+		"name=foo;other=bar",
+		"name=foo;count=",
+		"name=foo;validity=-1h",
+		"name=foo;list=a,,b",
+	} {
+		_, err := ParseArguments(generator, s)
+		if err == nil {
+			t.Errorf("expected error for arguments %q, but got none", s)
+		} else {
+			t.Logf("ok; got error: %s", err)
+		}
+	}
+}
+
+func TestRegistry(t *testing.T) {
+	registry := NewRegistry()
+
+	generator := NewGenerator("constant", Schema{
+		Arguments: []Argument{
+			{Name: "value"},
+		},
+	}, func(ctx context.Context, args Arguments) (string, error) {
+		return args.String("value", "constant") + "@" + EnvironmentFromContext(ctx).Namespace, nil
+	})
+	if err := registry.Register(generator); err != nil {
+		t.Fatalf("got error: %s", err)
+	}
+	if err := registry.Register(generator); err == nil {
+		t.Error("expected error registering generator twice, but got none")
+	} else {
+		t.Logf("ok; got error: %s", err)
+	}
+	if err := registry.Register(NewGenerator("In Valid", Schema{}, nil)); err == nil {
+		t.Error("expected error registering generator with invalid name, but got none")
+	} else {
+		t.Logf("ok; got error: %s", err)
+	}
+	if names := registry.Names(); len(names) != 1 || names[0] != "constant" {
+		t.Errorf("got invalid generator names: %v", names)
+	}
+
+	ctx := WithEnvironment(context.TODO(), &Environment{Namespace: "testing"})
+	value, err := registry.GenerateValue(ctx, "constant:value=foo")
+	if err != nil {
+		t.Fatalf("got error: %s", err)
+	}
+	if value != "foo@testing" {
+		t.Errorf("got invalid value: %s", value)
+	}
+	if _, err := registry.GenerateValue(ctx, "uuid"); err == nil {
+		t.Error("expected error for unregistered generator, but got none")
+	} else {
+		t.Logf("ok; got error: %s", err)
+	}
+}
+
+func TestDefaultRegistry(t *testing.T) {
+	for _, name := range []string{"password", "uuid", "username", "preset", "choice", "sequence", "timestamp", "mac", "ula", "port", "mtls", "ssh", "s3"} {
+		if _, ok := DefaultRegistry.Get(name); !ok {
+			t.Errorf("generator %s not registered", name)
+		}
+	}
+	for _, name := range []string{"mtls", "ssh", "s3"} {
+		generator, _ := DefaultRegistry.Get(name)
+		if _, ok := generator.(BundleGenerator); !ok {
+			t.Errorf("generator %s is not a bundle generator", name)
+		}
+	}
+}
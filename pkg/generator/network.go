@@ -0,0 +1,56 @@
+/*
+SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company and secret-generator contributors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package generator
+
+import (
+	"crypto/rand"
+	"fmt"
+	"io"
+	"math/big"
+	"net"
+	"net/netip"
+	"strconv"
+)
+
+// generate a random unicast MAC address with the locally administered bit set
+func generateMAC(random io.Reader) (string, error) {
+	mac := make(net.HardwareAddr, 6)
+	if _, err := io.ReadFull(random, mac); err != nil {
+		return "", err
+	}
+	mac[0] = (mac[0] | 0x02) &^ 0x01
+	return mac.String(), nil
+}
+
+// generate a random unique local IPv6 prefix (RFC 4193); the first 48 bits consist of fd and a random global id,
+// all further bits up to the given prefix length (i.e. the subnet id) are random as well
+func generateULAPrefix(random io.Reader, prefixLength int) (string, error) {
+	if prefixLength < 48 || prefixLength > 64 {
+		return "", fmt.Errorf("invalid prefix length %d (must be between 48 and 64)", prefixLength)
+	}
+	var addr [16]byte
+	if _, err := io.ReadFull(random, addr[1:8]); err != nil {
+		return "", err
+	}
+	addr[0] = 0xfd
+	prefix, err := netip.AddrFrom16(addr).Prefix(prefixLength)
+	if err != nil {
+		return "", err
+	}
+	return prefix.String(), nil
+}
+
+// generate a random port number between minPort and maxPort (inclusive)
+func generatePort(random io.Reader, minPort int, maxPort int) (string, error) {
+	if minPort < 1 || maxPort > 65535 || minPort > maxPort {
+		return "", fmt.Errorf("invalid port range %d-%d", minPort, maxPort)
+	}
+	n, err := rand.Int(random, big.NewInt(int64(maxPort-minPort+1)))
+	if err != nil {
+		return "", err
+	}
+	return strconv.Itoa(minPort + int(n.Int64())), nil
+}
@@ -0,0 +1,67 @@
+/*
+SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company and secret-generator contributors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package generator
+
+import (
+	"crypto/rand"
+	"net"
+	"net/netip"
+	"strconv"
+	"testing"
+)
+
+func TestGenerateMAC(t *testing.T) {
+	v, err := generateMAC(rand.Reader)
+	if err != nil {
+		t.Fatalf("generateMAC: got error: %s", err)
+	}
+	mac, err := net.ParseMAC(v)
+	if err != nil || len(mac) != 6 {
+		t.Fatalf("generateMAC: got invalid mac address: %s", v)
+	}
+	if mac[0]&0x02 == 0 || mac[0]&0x01 != 0 {
+		t.Errorf("generateMAC: got mac address which is not locally administered unicast: %s", v)
+	}
+}
+
+func TestGenerateULAPrefix(t *testing.T) {
+	for _, prefixLength := range []int{48, 56, 64} {
+		v, err := generateULAPrefix(rand.Reader, prefixLength)
+		if err != nil {
+			t.Fatalf("generateULAPrefix: got error: %s", err)
+		}
+		prefix, err := netip.ParsePrefix(v)
+		if err != nil {
+			t.Fatalf("generateULAPrefix: got invalid prefix: %s", v)
+		}
+		if prefix.Bits() != prefixLength || prefix.Masked() != prefix || !netip.MustParsePrefix("fd00::/8").Contains(prefix.Addr()) {
+			t.Errorf("generateULAPrefix: got invalid prefix: %s", v)
+		}
+	}
+
+	if _, err := generateULAPrefix(rand.Reader, 32); err == nil {
+		t.Error("generateULAPrefix: expected error, but got none")
+	}
+}
+
+func TestGeneratePort(t *testing.T) {
+	for i := 0; i < 100; i++ {
+		v, err := generatePort(rand.Reader, 30000, 30002)
+		if err != nil {
+			t.Fatalf("generatePort: got error: %s", err)
+		}
+		if port, err := strconv.Atoi(v); err != nil || port < 30000 || port > 30002 {
+			t.Errorf("generatePort: got invalid port: %s", v)
+		}
+	}
+
+	if _, err := generatePort(rand.Reader, 2000, 1000); err == nil {
+		t.Error("generatePort: expected error, but got none")
+	}
+	if _, err := generatePort(rand.Reader, 0, 1000); err == nil {
+		t.Error("generatePort: expected error, but got none")
+	}
+}
@@ -0,0 +1,348 @@
+/*
+SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company and secret-generator contributors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package generator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+const (
+	// DefaultPrefix is the default prefix of placeholders.
+	DefaultPrefix = "%generate"
+)
+
+var (
+	// ErrInvalidFormat is returned (wrapped) if a placeholder cannot be parsed.
+	ErrInvalidFormat = errors.New("invalid generator format")
+	// ErrUnsupportedType is returned (wrapped) if a placeholder references a generator type which is not registered,
+	// or a regular generator where a bundle generator is expected (or vice versa).
+	ErrUnsupportedType = errors.New("unsupported generator type")
+)
+
+// SyntaxError is returned if a placeholder cannot be parsed; it wraps ErrInvalidFormat.
+type SyntaxError struct {
+	// Position (1-based, in bytes) of the offending character, counted from the first character after the prefix and the separating colon.
+	Position int
+	// Message describes the problem.
+	Message string
+}
+
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("%s: %s at position %d", ErrInvalidFormat, e.Message, e.Position)
+}
+
+func (e *SyntaxError) Unwrap() error {
+	return ErrInvalidFormat
+}
+
+// ArgumentError is returned if the arguments of a placeholder do not match the schema of the generator.
+type ArgumentError struct {
+	// Generator type.
+	Generator string
+	// Argument is the offending argument, as specified (<arg>=<value>); empty if a required argument is missing.
+	Argument string
+	// Position (1-based, see SyntaxError) of the offending argument; zero if unknown.
+	Position int
+	// Reason optionally describes the problem in more detail.
+	Reason string
+}
+
+func (e *ArgumentError) Error() string {
+	at := ""
+	if e.Position > 0 {
+		at = fmt.Sprintf(" at position %d", e.Position)
+	}
+	switch {
+	case e.Argument == "":
+		return fmt.Sprintf("invalid %s generator arguments: %s", e.Generator, e.Reason)
+	case e.Reason == "":
+		return fmt.Sprintf("invalid %s generator argument%s: %s", e.Generator, at, e.Argument)
+	default:
+		return fmt.Sprintf("invalid %s generator argument%s: %s (%s)", e.Generator, at, e.Argument, e.Reason)
+	}
+}
+
+// Spec is a parsed placeholder, i.e. a generator type and its arguments.
+//
+// The grammar of placeholders (after the prefix) is:
+//
+//	spec     = [ ":" ] | type [ ":" [ argument *( ";" argument ) ] ]
+//	type     = 1*( any character except ":" )
+//	argument = [ name "=" ] value
+//	name     = 1*( ALPHA / DIGIT / "_" / "-" )
+//	value    = quoted / *( any character except ";" )
+//	quoted   = DQUOTE *( any character except DQUOTE and "\" / "\" any character ) DQUOTE
+//
+// An empty spec refers to the password generator. Quoting allows values containing ";"; note that a value is only
+// considered quoted if it starts with a double quote.
+type Spec struct {
+	// Type of the generator.
+	Type string
+	// Arguments in the order specified.
+	Arguments []SpecArgument
+}
+
+// SpecArgument is a (not yet validated) argument of a placeholder.
+type SpecArgument struct {
+	// Name of the argument; empty for a positional argument.
+	Name string
+	// Value of the argument (unquoted).
+	Value string
+	// Position (1-based, see SyntaxError) of the argument.
+	Position int
+}
+
+func (a SpecArgument) String() string {
+	value := a.Value
+	if strings.ContainsRune(value, ';') || strings.HasPrefix(value, `"`) || (a.Name == "" && strings.ContainsRune(value, '=')) {
+		value = `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(value) + `"`
+	}
+	if a.Name == "" {
+		return value
+	}
+	return a.Name + "=" + value
+}
+
+// String returns the spec in normalized form, such that it can be parsed again.
+func (s *Spec) String() string {
+	if len(s.Arguments) == 0 {
+		return s.Type
+	}
+	args := make([]string, len(s.Arguments))
+	for i, arg := range s.Arguments {
+		args[i] = arg.String()
+	}
+	return s.Type + ":" + strings.Join(args, ";")
+}
+
+// ParseValue checks if a (secret) value is a placeholder with the given prefix; if so, the part after the prefix (and the separating colon)
+// is returned, which can be passed to ParseSpec() or the generation functions.
+func ParseValue(value string, prefix string) (string, bool) {
+	if value == prefix {
+		return "", true
+	} else if strings.HasPrefix(value, prefix+":") {
+		return strings.TrimPrefix(value, prefix+":"), true
+	} else {
+		return "", false
+	}
+}
+
+// Unescape checks if a (secret) value is an escaped literal, i.e. the prefix, preceded by one or more times the first character of the prefix
+// (such as %%generate:uuid); if so, the value with one escape character removed is returned.
+func Unescape(value string, prefix string) (string, bool) {
+	if isEscaped(value, prefix) {
+		return value[1:], true
+	}
+	return "", false
+}
+
+// Escape returns a value, which is not interpreted as placeholder, but unescaped to the given value; if the value does not
+// start with the prefix (or an escaped prefix), it is returned as it is.
+func Escape(value string, prefix string) string {
+	if prefix != "" && (strings.HasPrefix(value, prefix) || isEscaped(value, prefix)) {
+		return prefix[:1] + value
+	}
+	return value
+}
+
+func isEscaped(value string, prefix string) bool {
+	if prefix == "" || !strings.HasPrefix(value, prefix[:1]) {
+		return false
+	}
+	for value = value[1:]; !strings.HasPrefix(value, prefix); value = value[1:] {
+		if !strings.HasPrefix(value, prefix[:1]) {
+			return false
+		}
+	}
+	return true
+}
+
+// ParseSpec parses the part of a placeholder after the prefix (<type>[:<arg>=<value>;...]), according to the grammar described at Spec;
+// syntax errors are returned as *SyntaxError.
+func ParseSpec(format string) (*Spec, error) {
+	if format == "" || format == ":" {
+		return &Spec{Type: "password"}, nil
+	}
+	generatorType, args, hasArgs := strings.Cut(format, ":")
+	if generatorType == "" {
+		return nil, &SyntaxError{Position: 1, Message: "missing generator type"}
+	}
+	spec := &Spec{Type: generatorType}
+	if hasArgs {
+		var err error
+		if spec.Arguments, err = parseArgumentList(args, len(generatorType)+1); err != nil {
+			return nil, err
+		}
+	}
+	return spec, nil
+}
+
+// parse a list of arguments; offset is the number of bytes preceding s in the placeholder (used for error positions)
+func parseArgumentList(s string, offset int) ([]SpecArgument, error) {
+	var list []SpecArgument
+	if s == "" {
+		return nil, nil
+	}
+	i := 0
+	for {
+		arg := SpecArgument{Position: offset + i + 1}
+		// the argument is named if a = occurs before any ; or (leading) "
+		if j := strings.IndexAny(s[i:], `=;"`); j >= 0 && s[i+j] == '=' {
+			arg.Name = s[i : i+j]
+			if !regexp.MustCompile(`^[A-Za-z0-9_-]+$`).MatchString(arg.Name) {
+				return nil, &SyntaxError{Position: offset + i + 1, Message: fmt.Sprintf("invalid argument name '%s'", arg.Name)}
+			}
+			i += j + 1
+		}
+		if i < len(s) && s[i] == '"' {
+			start := i
+			var value strings.Builder
+			closed := false
+			for i++; i < len(s); i++ {
+				if s[i] == '\\' && i+1 < len(s) {
+					i++
+					value.WriteByte(s[i])
+				} else if s[i] == '"' {
+					closed = true
+					i++
+					break
+				} else {
+					value.WriteByte(s[i])
+				}
+			}
+			if !closed {
+				return nil, &SyntaxError{Position: offset + start + 1, Message: "unterminated quoted value"}
+			}
+			if i < len(s) && s[i] != ';' {
+				return nil, &SyntaxError{Position: offset + i + 1, Message: "unexpected character after quoted value"}
+			}
+			arg.Value = value.String()
+		} else {
+			j := strings.IndexByte(s[i:], ';')
+			if j < 0 {
+				j = len(s) - i
+			}
+			arg.Value = s[i : i+j]
+			i += j
+			if arg.Name == "" && arg.Value == "" {
+				return nil, &SyntaxError{Position: offset + i + 1, Message: "empty argument"}
+			}
+		}
+		list = append(list, arg)
+		if i == len(s) {
+			return list, nil
+		}
+		// skip ;
+		i++
+		if i == len(s) {
+			return nil, &SyntaxError{Position: offset + i + 1, Message: "empty argument"}
+		}
+	}
+}
+
+// Parse looks up the generator referenced by format, and parses its arguments.
+func (r *Registry) Parse(format string) (Generator, Arguments, error) {
+	spec, err := ParseSpec(format)
+	if err != nil {
+		return nil, nil, err
+	}
+	generator, ok := r.Get(spec.Type)
+	if !ok {
+		return nil, nil, fmt.Errorf("%w: %s", ErrUnsupportedType, spec.Type)
+	}
+	args, err := bindArguments(generator, spec.Arguments)
+	if err != nil {
+		return nil, nil, err
+	}
+	return generator, args, nil
+}
+
+// IsBundle checks if format refers to a generator producing multiple keys (instead of replacing the value of the current key).
+func (r *Registry) IsBundle(format string) bool {
+	spec, err := ParseSpec(format)
+	if err != nil {
+		return false
+	}
+	generator, ok := r.Get(spec.Type)
+	if !ok {
+		return false
+	}
+	_, ok = generator.(BundleGenerator)
+	return ok
+}
+
+// IsRotatable checks if format refers to a generator producing credentials, which are rotated when all keys of a secret are rotated.
+func (r *Registry) IsRotatable(format string) bool {
+	spec, err := ParseSpec(format)
+	if err != nil {
+		return false
+	}
+	generator, ok := r.Get(spec.Type)
+	if !ok {
+		return false
+	}
+	return generator.Schema().Rotatable
+}
+
+// BundleKeys returns the keys produced by the bundle generator referenced by format.
+func (r *Registry) BundleKeys(format string) ([]string, error) {
+	generator, args, err := r.Parse(format)
+	if err != nil {
+		return nil, err
+	}
+	bundleGenerator, ok := generator.(BundleGenerator)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s is not a bundle generator", ErrUnsupportedType, generator.Name())
+	}
+	return bundleGenerator.Keys(args), nil
+}
+
+// GenerateBundle generates the values of the bundle generator referenced by format.
+func (r *Registry) GenerateBundle(ctx context.Context, format string) (Values, error) {
+	generator, args, err := r.Parse(format)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := generator.(BundleGenerator); !ok {
+		return nil, fmt.Errorf("%w: %s is not a bundle generator", ErrUnsupportedType, generator.Name())
+	}
+	return generator.Generate(ctx, args)
+}
+
+// GenerateValue generates a value with the (regular) generator referenced by format.
+func (r *Registry) GenerateValue(ctx context.Context, format string) (string, error) {
+	generator, args, err := r.Parse(format)
+	if err != nil {
+		return "", err
+	}
+	if _, ok := generator.(BundleGenerator); ok {
+		return "", fmt.Errorf("%w: %s produces multiple keys", ErrUnsupportedType, generator.Name())
+	}
+	values, err := generator.Generate(ctx, args)
+	if err != nil {
+		return "", err
+	}
+	value, ok := values[""]
+	if !ok {
+		return "", fmt.Errorf("generator type %s returned no value", generator.Name())
+	}
+	return value, nil
+}
+
+// GenerateValue generates a value with the built-in (or registered) generator referenced by format,
+// such as password:length=16 or uuid; generators needing cluster access expect an environment in ctx (see WithEnvironment()).
+func GenerateValue(ctx context.Context, format string) (string, error) {
+	return DefaultRegistry.GenerateValue(ctx, format)
+}
+
+// GenerateBundle generates the values of the built-in (or registered) bundle generator referenced by format, such as mtls.
+func GenerateBundle(ctx context.Context, format string) (Values, error) {
+	return DefaultRegistry.GenerateBundle(ctx, format)
+}
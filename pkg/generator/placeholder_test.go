@@ -0,0 +1,785 @@
+/*
+SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company and secret-generator contributors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package generator
+
+import (
+	"context"
+	"encoding/base32"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"maps"
+	"net"
+	"net/netip"
+	"regexp"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func testContext(client kubernetes.Interface, namespace string) context.Context {
+	return WithEnvironment(context.TODO(), &Environment{Client: client, Namespace: namespace})
+}
+
+func TestParseValue(t *testing.T) {
+	if format, ok := ParseValue("%generate:uuid", DefaultPrefix); !ok || format != "uuid" {
+		t.Errorf("got invalid format: %s", format)
+	}
+	if format, ok := ParseValue("%generate", DefaultPrefix); !ok || format != "" {
+		t.Errorf("got invalid format: %s", format)
+	}
+	if _, ok := ParseValue("%generateuuid", DefaultPrefix); ok {
+		t.Error("expected no placeholder, but got one")
+	}
+	if value, ok := Unescape("%%generate:uuid", DefaultPrefix); !ok || value != "%generate:uuid" {
+		t.Errorf("got invalid unescaped value: %s", value)
+	}
+	if _, ok := Unescape("%generate:uuid", DefaultPrefix); ok {
+		t.Error("expected no escaped value, but got one")
+	}
+	if _, ok := Unescape("%%other", DefaultPrefix); ok {
+		t.Error("expected no escaped value, but got one")
+	}
+	for _, value := range []string{"%generate", "%%generate:uuid", "%gen", "foo"} {
+		escaped := Escape(value, DefaultPrefix)
+		if _, ok := ParseValue(escaped, DefaultPrefix); ok {
+			t.Errorf("escaped value %s is a placeholder", escaped)
+		}
+		if unescaped, ok := Unescape(escaped, DefaultPrefix); ok && unescaped != value || !ok && escaped != value {
+			t.Errorf("escaped value %s does not unescape to %s", escaped, value)
+		}
+	}
+}
+
+func TestParseSpec(t *testing.T) {
+	spec, err := ParseSpec("")
+	if err != nil {
+		t.Fatalf("got error: %s", err)
+	}
+	if spec.Type != "password" || len(spec.Arguments) != 0 {
+		t.Errorf("got invalid spec: %s", spec)
+	}
+
+	spec, err = ParseSpec("password:length=8;symbols=:")
+	if err != nil {
+		t.Fatalf("got error: %s", err)
+	}
+	if spec.Type != "password" || len(spec.Arguments) != 2 || spec.Arguments[1] != (SpecArgument{Name: "symbols", Value: ":", Position: 19}) || spec.String() != "password:length=8;symbols=:" {
+		t.Errorf("got invalid spec: %s (%v)", spec, spec.Arguments)
+	}
+
+	spec, err = ParseSpec(`choice:values="a;b,c";separator="\\;\"";"x=y"`)
+	if err != nil {
+		t.Fatalf("got error: %s", err)
+	}
+	expected := []SpecArgument{
+		{Name: "values", Value: "a;b,c", Position: 8},
+		{Name: "separator", Value: `\;"`, Position: 23},
+		{Name: "", Value: "x=y", Position: 41},
+	}
+	if len(spec.Arguments) != len(expected) {
+		t.Fatalf("got invalid arguments: %v", spec.Arguments)
+	}
+	for i := range expected {
+		if spec.Arguments[i] != expected[i] {
+			t.Errorf("got invalid argument: %v (expected: %v)", spec.Arguments[i], expected[i])
+		}
+	}
+	reparsed, err := ParseSpec(spec.String())
+	if err != nil {
+		t.Fatalf("got error: %s", err)
+	}
+	if reparsed.String() != spec.String() {
+		t.Errorf("got different spec after reparsing: %s (expected: %s)", reparsed, spec)
+	}
+
+	for format, position := range map[string]int{
+		":uuid":                   1,
+		"password:length=8;":      19,
+		"password:;length=8":      10,
+		`password:symbols="!;?`:   18,
+		`password:symbols="!;?"x`: 23,
+		"password:len gth=8":      10,
+	} {
+		_, err := ParseSpec(format)
+		var syntaxError *SyntaxError
+		if !errors.As(err, &syntaxError) || !errors.Is(err, ErrInvalidFormat) {
+			t.Errorf("expected SyntaxError for %s, but got: %v", format, err)
+		} else if syntaxError.Position != position {
+			t.Errorf("got invalid error position for %s: %d (expected: %d)", format, syntaxError.Position, position)
+		} else {
+			t.Logf("ok; got error: %s", err)
+		}
+	}
+}
+
+func TestGenerateValueWithQuotedArgument(t *testing.T) {
+	v, err := GenerateValue(context.TODO(), `password:length=12;num_symbols=12;num_digits=0;symbols=";:"`)
+	if err != nil {
+		t.Fatalf("got error: %s", err)
+	}
+	if !regexp.MustCompile(`^[;:]{12}$`).MatchString(v) {
+		t.Errorf("got invalid password: %s", v)
+	}
+}
+
+func TestErrors(t *testing.T) {
+	_, err := GenerateValue(context.TODO(), "foobar")
+	if !errors.Is(err, ErrUnsupportedType) {
+		t.Errorf("expected ErrUnsupportedType, but got: %v", err)
+	}
+	_, err = GenerateValue(context.TODO(), "mtls")
+	if !errors.Is(err, ErrUnsupportedType) {
+		t.Errorf("expected ErrUnsupportedType, but got: %v", err)
+	}
+	_, err = GenerateValue(context.TODO(), "password:length=foo")
+	var argumentError *ArgumentError
+	if !errors.As(err, &argumentError) || argumentError.Generator != "password" || argumentError.Argument != "length=foo" {
+		t.Errorf("expected ArgumentError, but got: %v", err)
+	}
+	_, err = GenerateValue(context.TODO(), "sequence")
+	if !errors.As(err, &argumentError) || argumentError.Argument != "" {
+		t.Errorf("expected ArgumentError, but got: %v", err)
+	}
+}
+
+func TestGenerateValue(t *testing.T) {
+	var client kubernetes.Interface
+	var v string
+	var err error
+
+	// short form; will be interpreted as password without arguments
+	v, err = GenerateValue(testContext(client, "testing"), "")
+	if err != nil {
+		t.Fatalf("GenerateValue: got errror: %s", err)
+	}
+	if !regexp.MustCompile(`^[A-Za-z0-9` + Symbols + `]{32}$`).MatchString(v) {
+		t.Errorf("GenerateValue: got invalid password (wrong length): %s", v)
+	}
+	if len(regexp.MustCompile(`[A-Za-z]`).FindAllString(v, -1)) != 16 {
+		t.Errorf("GenerateValue: got invalid password (wrong letter count): %s", v)
+	}
+	if len(regexp.MustCompile(`[0-9]`).FindAllString(v, -1)) != 8 {
+		t.Errorf("GenerateValue: got invalid password (wrong digit count): %s", v)
+	}
+	if len(regexp.MustCompile(`[`+Symbols+`]`).FindAllString(v, -1)) != 8 {
+		t.Errorf("GenerateValue: got invalid password (wrong symbol count): %s", v)
+	}
+
+	// short form; will be interpreted as password without arguments
+	v, err = GenerateValue(testContext(client, "testing"), ":")
+	if err != nil {
+		t.Fatalf("GenerateValue: got errror: %s", err)
+	}
+	if !regexp.MustCompile(`^[A-Za-z0-9` + Symbols + `]{32}$`).MatchString(v) {
+		t.Errorf("GenerateValue: got invalid password (wrong length): %s", v)
+	}
+	if len(regexp.MustCompile(`[A-Za-z]`).FindAllString(v, -1)) != 16 {
+		t.Errorf("GenerateValue: got invalid password (wrong letter count): %s", v)
+	}
+	if len(regexp.MustCompile(`[0-9]`).FindAllString(v, -1)) != 8 {
+		t.Errorf("GenerateValue: got invalid password (wrong digit count): %s", v)
+	}
+	if len(regexp.MustCompile(`[`+Symbols+`]`).FindAllString(v, -1)) != 8 {
+		t.Errorf("GenerateValue: got invalid password (wrong symbol count): %s", v)
+	}
+
+	// password without arguments
+	v, err = GenerateValue(testContext(client, "testing"), "password")
+	if err != nil {
+		t.Fatalf("GenerateValue: got errror: %s", err)
+	}
+	if !regexp.MustCompile(`^[A-Za-z0-9` + Symbols + `]{32}$`).MatchString(v) {
+		t.Errorf("GenerateValue: got invalid password (wrong length): %s", v)
+	}
+	if len(regexp.MustCompile(`[A-Za-z]`).FindAllString(v, -1)) != 16 {
+		t.Errorf("GenerateValue: got invalid password (wrong letter count): %s", v)
+	}
+	if len(regexp.MustCompile(`[0-9]`).FindAllString(v, -1)) != 8 {
+		t.Errorf("GenerateValue: got invalid password (wrong digit count): %s", v)
+	}
+	if len(regexp.MustCompile(`[`+Symbols+`]`).FindAllString(v, -1)) != 8 {
+		t.Errorf("GenerateValue: got invalid password (wrong symbol count): %s", v)
+	}
+
+	// password with arguments
+	symbols := "_-"
+	v, err = GenerateValue(testContext(client, "testing"), "password:length=20;num_digits=3;num_symbols=4;symbols="+symbols)
+	if err != nil {
+		t.Fatalf("GenerateValue: got errror: %s", err)
+	}
+	if !regexp.MustCompile(`^[A-Za-z0-9` + symbols + `]{20}$`).MatchString(v) {
+		t.Errorf("GenerateValue: got invalid password (wrong length): %s", v)
+	}
+	if len(regexp.MustCompile(`[A-Za-z]`).FindAllString(v, -1)) != 13 {
+		t.Errorf("GenerateValue: got invalid password (wrong letter count): %s", v)
+	}
+	if len(regexp.MustCompile(`[0-9]`).FindAllString(v, -1)) != 3 {
+		t.Errorf("GenerateValue: got invalid password (wrong digit count): %s", v)
+	}
+	if len(regexp.MustCompile(`[`+symbols+`]`).FindAllString(v, -1)) != 4 {
+		t.Errorf("GenerateValue: got invalid password (wrong symbol count): %s", v)
+	}
+
+	// password with base32 encoding
+	v, err = GenerateValue(testContext(client, "testing"), "password:length=5;num_digits=0;num_symbols=5;symbols=_;encoding=base32")
+	if err != nil {
+		t.Fatalf("GenerateValue: got errror: %s", err)
+	}
+	if v != "L5PV6X27" {
+		// L5PV6X27 is base32 of _____
+		t.Errorf("GenerateValue: got invalid password (invalid base64 encoding): %s", v)
+	}
+
+	// password with base64 encoding
+	v, err = GenerateValue(testContext(client, "testing"), "password:length=5;num_digits=0;num_symbols=5;symbols=_;encoding=base64")
+	if err != nil {
+		t.Fatalf("GenerateValue: got errror: %s", err)
+	}
+	if v != "X19fX18=" {
+		// X19fX18= is base64 of _____
+		t.Errorf("GenerateValue: got invalid password (invalid base64 encoding): %s", v)
+	}
+
+	// password with base64_raw (without padding) encoding
+	v, err = GenerateValue(testContext(client, "testing"), "password:length=5;num_digits=0;num_symbols=5;symbols=_;encoding=base64_raw")
+	if err != nil {
+		t.Fatalf("GenerateValue: got errror: %s", err)
+	}
+	if v != "X19fX18" {
+		// X19fX18 is base64_raw of _____
+		t.Errorf("GenerateValue: got invalid password (invalid base64 encoding): %s", v)
+	}
+
+	// password with hex encoding
+	v, err = GenerateValue(testContext(client, "testing"), "password:length=5;num_digits=0;num_symbols=5;symbols=_;encoding=hex")
+	if err != nil {
+		t.Fatalf("GenerateValue: got errror: %s", err)
+	}
+	if v != "5f5f5f5f5f" {
+		// 5f5f5f5f5f is hex of _____
+		t.Errorf("GenerateValue: got invalid password (invalid hex encoding): %s", v)
+	}
+
+	// uuid
+	v, err = GenerateValue(testContext(client, "testing"), "uuid")
+	if err != nil {
+		t.Fatalf("GenerateValue: got errror: %s", err)
+	}
+	if _, err := uuid.Parse(string(v)); err != nil {
+		t.Errorf("GenerateValue: got invalid uuid; error: %s", err)
+	}
+
+	// uuid encoding base32
+	v, err = GenerateValue(testContext(client, "testing"), "uuid:encoding=base32")
+	if err != nil {
+		t.Fatalf("GenerateValue: got errror: %s", err)
+	}
+	var decodedUuidBytes []byte
+	decodedUuidBytes, _ = base32.StdEncoding.DecodeString(v)
+	if _, err := uuid.FromBytes(decodedUuidBytes); err != nil {
+		t.Errorf("GenerateValue: got invalid uuid; error: %s", err)
+	}
+
+	// uuid encoding base64
+	v, err = GenerateValue(testContext(client, "testing"), "uuid:encoding=base64")
+	if err != nil {
+		t.Fatalf("GenerateValue: got errror: %s", err)
+	}
+	decodedUuidBytes, _ = base64.StdEncoding.DecodeString(v)
+	if _, err := uuid.FromBytes(decodedUuidBytes); err != nil {
+		t.Errorf("GenerateValue: got invalid uuid; error: %s", err)
+	}
+
+	// uuid encoding base64 url
+	v, err = GenerateValue(testContext(client, "testing"), "uuid:encoding=base64_url")
+	if err != nil {
+		t.Fatalf("GenerateValue: got errror: %s", err)
+	}
+	decodedUuidBytes, _ = base64.URLEncoding.DecodeString(v)
+	if _, err := uuid.FromBytes(decodedUuidBytes); err != nil {
+		t.Errorf("GenerateValue: got invalid uuid; error: %s", err)
+	}
+
+	// uuid encoding base64_raw (without padding)
+	v, err = GenerateValue(testContext(client, "testing"), "uuid:encoding=base64_raw")
+	if err != nil {
+		t.Fatalf("GenerateValue: got errror: %s", err)
+	}
+	decodedUuidBytes, _ = base64.RawStdEncoding.DecodeString(v)
+	if _, err := uuid.FromBytes(decodedUuidBytes); err != nil {
+		t.Errorf("GenerateValue: got invalid uuid; error: %s", err)
+	}
+
+	// uuid encoding base64_raw url (without padding)
+	v, err = GenerateValue(testContext(client, "testing"), "uuid:encoding=base64_raw_url")
+	if err != nil {
+		t.Fatalf("GenerateValue: got errror: %s", err)
+	}
+	decodedUuidBytes, _ = base64.RawURLEncoding.DecodeString(v)
+	if _, err := uuid.FromBytes(decodedUuidBytes); err != nil {
+		t.Errorf("GenerateValue: got invalid uuid; error: %s", err)
+	}
+
+	// uuid encoding hex
+	v, err = GenerateValue(testContext(client, "testing"), "uuid:encoding=hex")
+	if err != nil {
+		t.Fatalf("GenerateValue: got errror: %s", err)
+	}
+	decodedUuidBytes, _ = hex.DecodeString(v)
+	if _, err := uuid.FromBytes(decodedUuidBytes); err != nil {
+		t.Errorf("GenerateValue: got invalid uuid; error: %s", err)
+	}
+
+	// mac address
+	v, err = GenerateValue(testContext(client, "testing"), "mac")
+	if err != nil {
+		t.Fatalf("GenerateValue: got errror: %s", err)
+	}
+	if _, err := net.ParseMAC(v); err != nil {
+		t.Errorf("GenerateValue: got invalid mac address; error: %s", err)
+	}
+
+	// ula prefix
+	v, err = GenerateValue(testContext(client, "testing"), "ula:prefix_length=64")
+	if err != nil {
+		t.Fatalf("GenerateValue: got errror: %s", err)
+	}
+	if prefix, err := netip.ParsePrefix(v); err != nil || prefix.Bits() != 64 {
+		t.Errorf("GenerateValue: got invalid ula prefix: %s", v)
+	}
+
+	// port
+	v, err = GenerateValue(testContext(client, "testing"), "port:min=1024;max=1024")
+	if err != nil {
+		t.Fatalf("GenerateValue: got errror: %s", err)
+	}
+	if v != "1024" {
+		t.Errorf("GenerateValue: got invalid port: %s", v)
+	}
+	// choice
+	v, err = GenerateValue(testContext(client, "testing"), "choice:values=a,b,c;count=2;separator= ")
+	if err != nil {
+		t.Fatalf("GenerateValue: got errror: %s", err)
+	}
+	if !regexp.MustCompile(`^([abc]) ([abc])$`).MatchString(v) || v[0] == v[2] {
+		t.Errorf("GenerateValue: got invalid choice: %s", v)
+	}
+
+	// timestamp
+	v, err = GenerateValue(testContext(client, "testing"), "timestamp:offset=90d")
+	if err != nil {
+		t.Fatalf("GenerateValue: got errror: %s", err)
+	}
+	if ts, err := time.Parse(time.RFC3339, v); err != nil || time.Until(ts) < 89*24*time.Hour || time.Until(ts) > 90*24*time.Hour {
+		t.Errorf("GenerateValue: got invalid timestamp: %s", v)
+	}
+
+	// timestamp with unix format
+	v, err = GenerateValue(testContext(client, "testing"), "timestamp:format=unix;offset=-1h")
+	if err != nil {
+		t.Fatalf("GenerateValue: got errror: %s", err)
+	}
+	if ts, err := strconv.ParseInt(v, 10, 64); err != nil || time.Since(time.Unix(ts, 0)) < 59*time.Minute || time.Since(time.Unix(ts, 0)) > 61*time.Minute {
+		t.Errorf("GenerateValue: got invalid timestamp: %s", v)
+	}
+
+	// timestamp with custom layout
+	v, err = GenerateValue(testContext(client, "testing"), "timestamp:layout=2006-01-02 15:04")
+	if err != nil {
+		t.Fatalf("GenerateValue: got errror: %s", err)
+	}
+	if _, err := time.Parse("2006-01-02 15:04", v); err != nil {
+		t.Errorf("GenerateValue: got invalid timestamp: %s", v)
+	}
+
+	// username
+	v, err = GenerateValue(testContext(client, "testing"), "username:prefix=app;length=12;alphabet=lower")
+	if err != nil {
+		t.Fatalf("GenerateValue: got errror: %s", err)
+	}
+	if !regexp.MustCompile(`^app-[a-z]{8}$`).MatchString(v) {
+		t.Errorf("GenerateValue: got invalid username: %s", v)
+	}
+
+	// username with words style
+	v, err = GenerateValue(testContext(client, "testing"), "username:style=words;separator=_;max_length=32")
+	if err != nil {
+		t.Fatalf("GenerateValue: got errror: %s", err)
+	}
+	if !regexp.MustCompile(`^[a-z]+_[a-z]+$`).MatchString(v) {
+		t.Errorf("GenerateValue: got invalid username: %s", v)
+	}
+
+	// preset
+	v, err = GenerateValue(testContext(client, "testing"), "preset:erlang-cookie")
+	if err != nil {
+		t.Fatalf("GenerateValue: got errror: %s", err)
+	}
+	if !regexp.MustCompile(`^[A-Z]{20}$`).MatchString(v) {
+		t.Errorf("GenerateValue: got invalid erlang cookie: %s", v)
+	}
+}
+
+func TestGenerateValueWithConfigMap(t *testing.T) {
+	configMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "testing",
+			Name:      "slots",
+		},
+		Data: map[string]string{
+			"windows": "# maintenance windows\nmon-02:00\n\n  tue-02:00  \n",
+		},
+	}
+	client := fake.NewClientset(configMap)
+
+	v, err := GenerateValue(testContext(client, "testing"), "choice:config_map=slots;key=windows")
+	if err != nil {
+		t.Fatalf("GenerateValue: got errror: %s", err)
+	}
+	if v != "mon-02:00" && v != "tue-02:00" {
+		t.Errorf("GenerateValue: got invalid choice: %s", v)
+	}
+
+	// missing key
+	_, err = GenerateValue(testContext(client, "testing"), "choice:config_map=slots;key=regions")
+	if err == nil {
+		t.Error("GenerateValue: expected error, but got none")
+	} else {
+		t.Logf("ok; got error: %s", err)
+	}
+
+	// missing config map
+	_, err = GenerateValue(testContext(client, "other"), "choice:config_map=slots;key=windows")
+	if err == nil {
+		t.Error("GenerateValue: expected error, but got none")
+	} else {
+		t.Logf("ok; got error: %s", err)
+	}
+}
+
+func TestGenerateValueWithSequence(t *testing.T) {
+	client := fake.NewClientset()
+
+	for _, expected := range []string{"10", "11"} {
+		v, err := GenerateValue(testContext(client, "testing"), "sequence:name=tenant;start=10")
+		if err != nil {
+			t.Fatalf("GenerateValue: got errror: %s", err)
+		}
+		if v != expected {
+			t.Errorf("GenerateValue: got invalid sequence value: %s (expected: %s)", v, expected)
+		}
+	}
+
+	// counters of cluster scope are shared across namespaces, counters of namespace scope are not
+	for _, namespace := range []string{"tenant-a", "tenant-b"} {
+		ctx := WithEnvironment(context.TODO(), &Environment{Client: client, Namespace: namespace, SequenceNamespace: "system"})
+		for _, format := range []string{"sequence:name=tenant", "sequence:name=tenant;scope=namespace"} {
+			if _, err := GenerateValue(ctx, format); err != nil {
+				t.Fatalf("GenerateValue: got errror: %s", err)
+			}
+		}
+	}
+	configMap, err := client.CoreV1().ConfigMaps("system").Get(context.TODO(), SequenceConfigMapName, metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("GenerateValue: got error reading config map: %s", err)
+	}
+	if expected := map[string]string{"tenant": "2", "tenant-a.tenant": "1", "tenant-b.tenant": "1"}; !maps.Equal(configMap.Data, expected) {
+		t.Errorf("GenerateValue: got invalid sequence config map data: %v (expected: %v)", configMap.Data, expected)
+	}
+
+	// counter names must not contain dots, such that they cannot collide with counters of namespace scope
+	// (e.g. counter tenant-a.tenant of cluster scope with counter tenant of namespace scope in namespace tenant-a)
+	if _, err := GenerateValue(testContext(client, "testing"), "sequence:name=tenant-a.tenant"); err == nil {
+		t.Error("GenerateValue: expected error, but got none")
+	} else {
+		t.Logf("ok; got error: %s", err)
+	}
+}
+
+func TestGenerateValueWithError(t *testing.T) {
+	var client kubernetes.Interface
+	var err error
+
+	// invalid generator
+	_, err = GenerateValue(testContext(client, "testing"), "foobar")
+	if err == nil {
+		t.Error("GenerateValue: expected error, but got none")
+	} else {
+		t.Logf("ok; got error: %s", err)
+	}
+
+	// invalid password argument
+	_, err = GenerateValue(testContext(client, "testing"), "password:foo=bar")
+	if err == nil {
+		t.Error("GenerateValue: expected error, but got none")
+	} else {
+		t.Logf("ok; got error: %s", err)
+	}
+
+	// invalid password argument: length
+	_, err = GenerateValue(testContext(client, "testing"), "password:length=foo")
+	if err == nil {
+		t.Error("GenerateValue: expected error, but got none")
+	} else {
+		t.Logf("ok; got error: %s", err)
+	}
+
+	// invalid password argument: number of digits
+	_, err = GenerateValue(testContext(client, "testing"), "password:num_digits=foo")
+	if err == nil {
+		t.Error("GenerateValue: expected error, but got none")
+	} else {
+		t.Logf("ok; got error: %s", err)
+	}
+
+	// invalid password argument: number of symbols
+	_, err = GenerateValue(testContext(client, "testing"), "password:num_symbols=foo")
+	if err == nil {
+		t.Error("GenerateValue: expected error, but got none")
+	} else {
+		t.Logf("ok; got error: %s", err)
+	}
+
+	// invalid password argument: number of symbols
+	_, err = GenerateValue(testContext(client, "testing"), "password:symbols=foo")
+	if err == nil {
+		t.Error("GenerateValue: expected error, but got none")
+	} else {
+		t.Logf("ok; got error: %s", err)
+	}
+
+	// invalid password argument: encoding
+	_, err = GenerateValue(testContext(client, "testing"), "password:encoding=foo")
+	if err == nil {
+		t.Error("GenerateValue: expected error, but got none")
+	} else {
+		t.Logf("ok; got error: %s", err)
+	}
+
+	// error during password generation: too many digits/symbols (symbols will default to 4/4 = 1 here)
+	_, err = GenerateValue(testContext(client, "testing"), "password:length=4;num_digits=4")
+	if err == nil {
+		t.Error("GenerateValue: expected error, but got none")
+	} else {
+		t.Logf("ok; got error: %s", err)
+	}
+
+	// invalid mac argument
+	_, err = GenerateValue(testContext(client, "testing"), "mac:foo=bar")
+	if err == nil {
+		t.Error("GenerateValue: expected error, but got none")
+	} else {
+		t.Logf("ok; got error: %s", err)
+	}
+
+	// invalid ula argument: prefix length
+	_, err = GenerateValue(testContext(client, "testing"), "ula:prefix_length=96")
+	if err == nil {
+		t.Error("GenerateValue: expected error, but got none")
+	} else {
+		t.Logf("ok; got error: %s", err)
+	}
+
+	// invalid port argument: range
+	_, err = GenerateValue(testContext(client, "testing"), "port:min=70000")
+	if err == nil {
+		t.Error("GenerateValue: expected error, but got none")
+	} else {
+		t.Logf("ok; got error: %s", err)
+	}
+
+	// invalid choice arguments: count exceeds number of values
+	_, err = GenerateValue(testContext(client, "testing"), "choice:values=a,b;count=3")
+	if err == nil {
+		t.Error("GenerateValue: expected error, but got none")
+	} else {
+		t.Logf("ok; got error: %s", err)
+	}
+
+	// invalid choice arguments: neither values nor config map
+	_, err = GenerateValue(testContext(client, "testing"), "choice:count=1")
+	if err == nil {
+		t.Error("GenerateValue: expected error, but got none")
+	} else {
+		t.Logf("ok; got error: %s", err)
+	}
+
+	// invalid choice arguments: config map without key
+	_, err = GenerateValue(testContext(client, "testing"), "choice:config_map=slots")
+	if err == nil {
+		t.Error("GenerateValue: expected error, but got none")
+	} else {
+		t.Logf("ok; got error: %s", err)
+	}
+
+	// invalid sequence arguments: missing name
+	_, err = GenerateValue(testContext(client, "testing"), "sequence:start=1")
+	if err == nil {
+		t.Error("GenerateValue: expected error, but got none")
+	} else {
+		t.Logf("ok; got error: %s", err)
+	}
+
+	// invalid timestamp argument: offset
+	_, err = GenerateValue(testContext(client, "testing"), "timestamp:offset=foo")
+	if err == nil {
+		t.Error("GenerateValue: expected error, but got none")
+	} else {
+		t.Logf("ok; got error: %s", err)
+	}
+
+	// invalid timestamp argument: format
+	_, err = GenerateValue(testContext(client, "testing"), "timestamp:format=iso8601")
+	if err == nil {
+		t.Error("GenerateValue: expected error, but got none")
+	} else {
+		t.Logf("ok; got error: %s", err)
+	}
+
+	// invalid username argument: separator
+	_, err = GenerateValue(testContext(client, "testing"), "username:separator=+")
+	if err == nil {
+		t.Error("GenerateValue: expected error, but got none")
+	} else {
+		t.Logf("ok; got error: %s", err)
+	}
+
+	// invalid username argument: prefix
+	for _, prefix := range []string{"App", "app_", "-app"} {
+		_, err = GenerateValue(testContext(client, "testing"), "username:prefix="+prefix)
+		if err == nil {
+			t.Errorf("GenerateValue: expected error for prefix %s, but got none", prefix)
+		} else {
+			t.Logf("ok; got error: %s", err)
+		}
+	}
+
+	// username exceeding maximum length with prefix
+	_, err = GenerateValue(testContext(client, "testing"), "username:prefix=app;length=10;max_length=3")
+	if err == nil {
+		t.Error("GenerateValue: expected error, but got none")
+	} else {
+		t.Logf("ok; got error: %s", err)
+	}
+
+	// invalid preset
+	_, err = GenerateValue(testContext(client, "testing"), "preset:foo")
+	if err == nil {
+		t.Error("GenerateValue: expected error, but got none")
+	} else {
+		t.Logf("ok; got error: %s", err)
+	}
+
+	// invalid preset argument
+	_, err = GenerateValue(testContext(client, "testing"), "preset:foo=laravel-app-key")
+	if err == nil {
+		t.Error("GenerateValue: expected error, but got none")
+	} else {
+		t.Logf("ok; got error: %s", err)
+	}
+
+	// invalid format
+	_, err = GenerateValue(testContext(client, "testing"), "::foo")
+	if err == nil {
+		t.Error("GenerateValue: expected error, but got none")
+	} else {
+		t.Logf("ok; got error: %s", err)
+	}
+
+	// invalid uuid argument
+	_, err = GenerateValue(testContext(client, "testing"), "uuid:foo=bar")
+	if err == nil {
+		t.Error("GenerateValue: expected error, but got none")
+	} else {
+		t.Logf("ok; got error: %s", err)
+	}
+}
+
+func TestGenerateBundleWithError(t *testing.T) {
+	var client kubernetes.Interface
+	var err error
+
+	// invalid bundle generator
+	_, err = GenerateBundle(testContext(client, "testing"), "uuid")
+	if err == nil {
+		t.Error("GenerateBundle: expected error, but got none")
+	} else {
+		t.Logf("ok; got error: %s", err)
+	}
+
+	// invalid mtls argument
+	_, err = GenerateBundle(testContext(client, "testing"), "mtls:foo=bar")
+	if err == nil {
+		t.Error("GenerateBundle: expected error, but got none")
+	} else {
+		t.Logf("ok; got error: %s", err)
+	}
+
+	// invalid mtls argument: ip addresses
+	_, err = GenerateBundle(testContext(client, "testing"), "mtls:ip_addresses=10.0.0.1,foo")
+	if err == nil {
+		t.Error("GenerateBundle: expected error, but got none")
+	} else {
+		t.Logf("ok; got error: %s", err)
+	}
+
+	// invalid mtls argument: key algorithm
+	_, err = GenerateBundle(testContext(client, "testing"), "mtls:key_algorithm=dsa")
+	if err == nil {
+		t.Error("GenerateBundle: expected error, but got none")
+	} else {
+		t.Logf("ok; got error: %s", err)
+	}
+
+	// invalid mtls argument: validity
+	_, err = GenerateBundle(testContext(client, "testing"), "mtls:validity=-1h")
+	if err == nil {
+		t.Error("GenerateBundle: expected error, but got none")
+	} else {
+		t.Logf("ok; got error: %s", err)
+	}
+
+	// invalid mtls argument: key prefix
+	_, err = GenerateBundle(testContext(client, "testing"), "mtls:key_prefix=db/")
+	if err == nil {
+		t.Error("GenerateBundle: expected error, but got none")
+	} else {
+		t.Logf("ok; got error: %s", err)
+	}
+
+	// invalid ssh argument
+	_, err = GenerateBundle(testContext(client, "testing"), "ssh:foo=bar")
+	if err == nil {
+		t.Error("GenerateBundle: expected error, but got none")
+	} else {
+		t.Logf("ok; got error: %s", err)
+	}
+
+	// invalid ssh argument: certificate type
+	_, err = GenerateBundle(testContext(client, "testing"), "ssh:cert_type=foo")
+	if err == nil {
+		t.Error("GenerateBundle: expected error, but got none")
+	} else {
+		t.Logf("ok; got error: %s", err)
+	}
+
+	// host certificate without principals
+	_, err = GenerateBundle(testContext(client, "testing"), "ssh:ca_secret=ssh-ca;cert_type=host")
+	var argumentError *ArgumentError
+	if !errors.As(err, &argumentError) {
+		t.Errorf("GenerateBundle: expected argument error, but got: %v", err)
+	} else {
+		t.Logf("ok; got error: %s", err)
+	}
+}
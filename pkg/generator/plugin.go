@@ -0,0 +1,150 @@
+/*
+SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company and secret-generator contributors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package generator
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	DefaultPluginTimeout = 10 * time.Second
+	// maximum size of the response of a plugin (the maximum size of a secret)
+	maxPluginOutput = 1 << 20
+	// maximum size of the error output of a plugin which is reported
+	maxPluginErrorOutput = 4 << 10
+)
+
+// PluginRequest is written (as JSON) to the standard input of a generator plugin.
+type PluginRequest struct {
+	// Type is the generator type the plugin was registered for.
+	Type string `json:"type"`
+	// Arguments of the placeholder, by name.
+	Arguments map[string]string `json:"arguments"`
+	// Namespace of the secret being mutated.
+	Namespace string `json:"namespace"`
+	// DryRun indicates that the admission request is a dry run (such as the diff of a GitOps tool); plugins must not cause
+	// side effects then (such as issuing or registering tokens), but should return a preview value.
+	DryRun bool `json:"dryRun"`
+}
+
+// PluginResponse is read (as JSON) from the standard output of a generator plugin.
+type PluginResponse struct {
+	// Value generated by the plugin; ignored if Error is set.
+	Value string `json:"value"`
+	// Error is set by the plugin if it cannot generate a value, e.g. because of invalid arguments;
+	// it is returned in the admission response.
+	Error string `json:"error,omitempty"`
+}
+
+// buffer keeping at most limit bytes of the written data, and discarding the rest (such that the writer is never blocked);
+// the buffer is not embedded, since its ReadFrom() method would bypass the limit when used with io.Copy()
+type limitedBuffer struct {
+	buffer    bytes.Buffer
+	limit     int
+	truncated bool
+}
+
+func (b *limitedBuffer) Write(p []byte) (int, error) {
+	if n := b.limit - b.buffer.Len(); len(p) > n {
+		b.buffer.Write(p[:max(n, 0)])
+		b.truncated = true
+		return len(p), nil
+	}
+	return b.buffer.Write(p)
+}
+
+func (b *limitedBuffer) Bytes() []byte {
+	return b.buffer.Bytes()
+}
+
+func (b *limitedBuffer) String() string {
+	return b.buffer.String()
+}
+
+type execGenerator struct {
+	name    string
+	path    string
+	timeout time.Duration
+}
+
+// NewExecGenerator creates a generator delegating to an external executable; the executable is called once per generated value,
+// receives a PluginRequest on its standard input, and must write a PluginResponse to its standard output, and exit with zero
+// within the given timeout (if zero, DefaultPluginTimeout is used). Arguments are not validated, but passed as they are.
+func NewExecGenerator(name string, path string, timeout time.Duration) Generator {
+	if timeout <= 0 {
+		timeout = DefaultPluginTimeout
+	}
+	return &execGenerator{name: name, path: path, timeout: timeout}
+}
+
+func (g *execGenerator) Name() string {
+	return g.name
+}
+
+func (g *execGenerator) Schema() Schema {
+	return Schema{AdditionalArguments: true}
+}
+
+func (g *execGenerator) Generate(ctx context.Context, args Arguments) (Values, error) {
+	ctx, cancel := context.WithTimeout(ctx, g.timeout)
+	defer cancel()
+
+	env := EnvironmentFromContext(ctx)
+	input, err := json.Marshal(&PluginRequest{
+		Type:      g.name,
+		Arguments: args,
+		Namespace: env.Namespace,
+		DryRun:    env.DryRun,
+	})
+	if err != nil {
+		return nil, err
+	}
+	stdout := &limitedBuffer{limit: maxPluginOutput}
+	stderr := &limitedBuffer{limit: maxPluginErrorOutput}
+	cmd := exec.CommandContext(ctx, g.path)
+	cmd.Stdin = bytes.NewReader(input)
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+	// don't wait for the output of orphaned child processes of the plugin after it was killed
+	cmd.WaitDelay = time.Second
+	if err := cmd.Run(); err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return nil, fmt.Errorf("plugin %s timed out after %s", g.name, g.timeout)
+		}
+		if message := strings.TrimSpace(stderr.String()); message != "" {
+			return nil, fmt.Errorf("plugin %s failed: %s (%s)", g.name, err, message)
+		}
+		return nil, errors.Wrapf(err, "plugin %s failed", g.name)
+	}
+	if stdout.truncated {
+		return nil, fmt.Errorf("plugin %s returned a response exceeding %d bytes", g.name, maxPluginOutput)
+	}
+	response := &PluginResponse{}
+	if err := json.Unmarshal(stdout.Bytes(), response); err != nil {
+		return nil, errors.Wrapf(err, "plugin %s returned an invalid response", g.name)
+	}
+	if response.Error != "" {
+		return nil, fmt.Errorf("plugin %s: %s", g.name, response.Error)
+	}
+	return Values{"": response.Value}, nil
+}
+
+// ParsePluginSpec parses a plugin specification of the form <type>=<path>, as passed to the --generator-plugin flag.
+func ParsePluginSpec(spec string) (string, string, error) {
+	name, path, ok := strings.Cut(spec, "=")
+	if !ok || name == "" || path == "" {
+		return "", "", fmt.Errorf("invalid plugin specification %s (expected <type>=<path>)", spec)
+	}
+	return name, path, nil
+}
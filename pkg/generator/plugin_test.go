@@ -0,0 +1,89 @@
+/*
+SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company and secret-generator contributors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package generator
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func writePlugin(t *testing.T, script string) string {
+	path := filepath.Join(t.TempDir(), "plugin")
+	if err := os.WriteFile(path, []byte("#!/bin/sh\n"+script+"\n"), 0o755); err != nil {
+		t.Fatalf("error writing plugin: %s", err)
+	}
+	return path
+}
+
+func TestExecGenerator(t *testing.T) {
+	// the plugin echoes its request as value
+	path := writePlugin(t, `printf '{"value":%s}' "$(sed 's/\\/\\\\/g; s/"/\\"/g; s/^/"/; s/$/"/')"`)
+	registry := NewRegistry()
+	if err := registry.Register(NewExecGenerator("echo", path, 0)); err != nil {
+		t.Fatalf("got error: %s", err)
+	}
+
+	value, err := registry.GenerateValue(WithEnvironment(context.TODO(), &Environment{Namespace: "testing"}), "echo:foo=bar;baz=")
+	if err != nil {
+		t.Fatalf("got error: %s", err)
+	}
+	expected := `{"type":"echo","arguments":{"baz":"","foo":"bar"},"namespace":"testing","dryRun":false}`
+	if value != expected {
+		t.Errorf("got invalid value: %s (expected: %s)", value, expected)
+	}
+
+	value, err = registry.GenerateValue(WithEnvironment(context.TODO(), &Environment{Namespace: "testing", DryRun: true}), "echo")
+	if err != nil {
+		t.Fatalf("got error: %s", err)
+	}
+	expected = `{"type":"echo","arguments":{},"namespace":"testing","dryRun":true}`
+	if value != expected {
+		t.Errorf("got invalid value: %s (expected: %s)", value, expected)
+	}
+}
+
+func TestExecGeneratorWithError(t *testing.T) {
+	for _, script := range []string{
+		`echo '{"error":"unsupported flavor"}'`,
+		`echo 'boom' >&2; exit 3`,
+		`exit 1`,
+		`echo 'not json'`,
+		`sleep 5`,
+		`printf '{"value":"'; head -c 2000000 /dev/zero | tr '\0' 'x'; printf '"}'`,
+	} {
+		g := NewExecGenerator("test", writePlugin(t, script), 500*time.Millisecond)
+		if _, err := g.Generate(context.TODO(), Arguments{}); err == nil {
+			t.Errorf("expected error for plugin %q, but got none", script)
+		} else {
+			t.Logf("ok; got error: %s", err)
+		}
+	}
+
+	g := NewExecGenerator("test", filepath.Join(t.TempDir(), "missing"), 0)
+	if _, err := g.Generate(context.TODO(), Arguments{}); err == nil {
+		t.Error("expected error for missing plugin, but got none")
+	} else {
+		t.Logf("ok; got error: %s", err)
+	}
+}
+
+func TestParsePluginSpec(t *testing.T) {
+	name, path, err := ParsePluginSpec("vault-token=/plugins/vault-token")
+	if err != nil {
+		t.Fatalf("got error: %s", err)
+	}
+	if name != "vault-token" || path != "/plugins/vault-token" {
+		t.Errorf("got invalid specification: %s, %s", name, path)
+	}
+	for _, spec := range []string{"", "foo", "=bar", "foo="} {
+		if _, _, err := ParsePluginSpec(spec); err == nil {
+			t.Errorf("expected error for specification %q, but got none", spec)
+		}
+	}
+}
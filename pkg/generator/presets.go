@@ -0,0 +1,75 @@
+/*
+SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company and secret-generator contributors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package generator
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"io"
+)
+
+const (
+	alphabetUpper  = "ABCDEFGHIJKLMNOPQRSTUVWXYZ"
+	alphabetLower  = "abcdefghijklmnopqrstuvwxyz"
+	alphabetDigits = "0123456789"
+)
+
+// catalog of well-known application secret formats; each entry generates a value in the format expected by the according application
+var presets = map[string]func(random io.Reader) (string, error){
+	// django.core.management.utils.get_random_secret_key()
+	"django-secret-key": func(random io.Reader) (string, error) {
+		return randomString(random, alphabetLower+alphabetDigits+"!@#$%^&*(-_=+)", 50)
+	},
+	// bin/rails secret
+	"rails-secret-key-base": func(random io.Reader) (string, error) {
+		return randomEncodedBytes(random, 64, hex.EncodeToString)
+	},
+	// php artisan key:generate (AES-256-CBC)
+	"laravel-app-key": func(random io.Reader) (string, error) {
+		value, err := randomEncodedBytes(random, 32, base64.StdEncoding.EncodeToString)
+		return "base64:" + value, err
+	},
+	// openssl rand -base64 756 (replica set/sharded cluster keyfile)
+	"mongodb-keyfile": func(random io.Reader) (string, error) {
+		return randomEncodedBytes(random, 756, base64.StdEncoding.EncodeToString)
+	},
+	// .erlang.cookie (e.g. RabbitMQ)
+	"erlang-cookie": func(random io.Reader) (string, error) {
+		return randomString(random, alphabetUpper, 20)
+	},
+	// key of an aescbc provider in a kubernetes EncryptionConfiguration
+	"kubernetes-aescbc-key": func(random io.Reader) (string, error) {
+		return randomEncodedBytes(random, 32, base64.StdEncoding.EncodeToString)
+	},
+	// key of a secretbox provider in a kubernetes EncryptionConfiguration
+	"kubernetes-secretbox-key": func(random io.Reader) (string, error) {
+		return randomEncodedBytes(random, 32, base64.StdEncoding.EncodeToString)
+	},
+}
+
+const (
+	S3KeyAccessKeyId     = "access-key-id"
+	S3KeySecretAccessKey = "secret-access-key"
+)
+
+var s3Keys = []string{S3KeyAccessKeyId, S3KeySecretAccessKey}
+
+// generate an S3-style (AWS, MinIO, Ceph RGW) credential pair, i.e. an access key id and the secret access key belonging to it;
+// the pair is generated (and rotated) together, since a new secret access key is useless without the according access key id
+func generateS3Credentials(random io.Reader) (Values, error) {
+	accessKeyId, err := randomString(random, alphabetUpper+alphabetDigits, 20)
+	if err != nil {
+		return nil, err
+	}
+	secretAccessKey, err := randomString(random, alphabetUpper+alphabetLower+alphabetDigits+"+/", 40)
+	if err != nil {
+		return nil, err
+	}
+	return Values{
+		S3KeyAccessKeyId:     accessKeyId,
+		S3KeySecretAccessKey: secretAccessKey,
+	}, nil
+}
@@ -0,0 +1,61 @@
+/*
+SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company and secret-generator contributors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package generator
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestPresets(t *testing.T) {
+	patterns := map[string]string{
+		"django-secret-key":        `^[a-z0-9!@#$%^&*(\-_=+)]{50}$`,
+		"rails-secret-key-base":    `^[0-9a-f]{128}$`,
+		"laravel-app-key":          `^base64:[A-Za-z0-9+/]{43}=$`,
+		"mongodb-keyfile":          `^[A-Za-z0-9+/]{1000}[A-Za-z0-9+/]{8}$`,
+		"erlang-cookie":            `^[A-Z]{20}$`,
+		"kubernetes-aescbc-key":    `^[A-Za-z0-9+/]{43}=$`,
+		"kubernetes-secretbox-key": `^[A-Za-z0-9+/]{43}=$`,
+	}
+	for name, preset := range presets {
+		pattern, ok := patterns[name]
+		if !ok {
+			t.Errorf("preset %s: missing test pattern", name)
+			continue
+		}
+		v, err := preset(rand.Reader)
+		if err != nil {
+			t.Fatalf("preset %s: got error: %s", name, err)
+		}
+		if !regexp.MustCompile(pattern).MatchString(v) {
+			t.Errorf("preset %s: got invalid value: %s", name, v)
+		}
+	}
+
+	v, _ := presets["laravel-app-key"](rand.Reader)
+	if key, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(v, "base64:")); err != nil || len(key) != 32 {
+		t.Errorf("preset laravel-app-key: got invalid key: %s", v)
+	}
+}
+
+func TestGenerateS3Credentials(t *testing.T) {
+	values, err := generateS3Credentials(rand.Reader)
+	if err != nil {
+		t.Fatalf("generateS3Credentials: got error: %s", err)
+	}
+	if len(values) != 2 {
+		t.Errorf("generateS3Credentials: got invalid values: %v", values)
+	}
+	if v := values[S3KeyAccessKeyId]; !regexp.MustCompile(`^[A-Z0-9]{20}$`).MatchString(v) {
+		t.Errorf("generateS3Credentials: got invalid access key id: %s", v)
+	}
+	if v := values[S3KeySecretAccessKey]; !regexp.MustCompile(`^[A-Za-z0-9+/]{40}$`).MatchString(v) {
+		t.Errorf("generateS3Credentials: got invalid secret access key: %s", v)
+	}
+}
@@ -0,0 +1,94 @@
+/*
+SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company and secret-generator contributors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package generator
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/pkg/errors"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/util/retry"
+)
+
+const (
+	SequenceConfigMapName = "secret-generator-sequences"
+)
+
+// assign the next value of the named counter; counters are persisted in a config map in the given namespace,
+// which is updated with optimistic concurrency, such that concurrent webhook calls (also across replicas) never
+// assign the same value twice; note that values may be skipped (e.g. if the admission request fails afterwards);
+// on dry runs, the value which would be assigned next is returned, without updating the counter
+func nextSequenceValue(ctx context.Context, client kubernetes.Interface, namespace string, name string, start int64, dryRun bool) (int64, error) {
+	if client == nil {
+		return 0, fmt.Errorf("unable to read sequence %s: no kubernetes client configured", name)
+	}
+	configMaps := client.CoreV1().ConfigMaps(namespace)
+	if dryRun {
+		configMap, err := configMaps.Get(ctx, SequenceConfigMapName, metav1.GetOptions{})
+		if apierrors.IsNotFound(err) {
+			return start, nil
+		} else if err != nil {
+			return 0, errors.Wrapf(err, "error reading sequence %s", name)
+		}
+		return nextValue(configMap, name, start)
+	}
+	var value int64
+	retriable := func(err error) bool {
+		return apierrors.IsConflict(err) || apierrors.IsAlreadyExists(err)
+	}
+	err := retry.OnError(retry.DefaultRetry, retriable, func() error {
+		configMap, err := configMaps.Get(ctx, SequenceConfigMapName, metav1.GetOptions{})
+		if apierrors.IsNotFound(err) {
+			value = start
+			configMap = &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: namespace,
+					Name:      SequenceConfigMapName,
+				},
+				Data: map[string]string{
+					name: strconv.FormatInt(value, 10),
+				},
+			}
+			_, err = configMaps.Create(ctx, configMap, metav1.CreateOptions{})
+			return err
+		} else if err != nil {
+			return err
+		}
+		value, err = nextValue(configMap, name, start)
+		if err != nil {
+			return err
+		}
+		if configMap.Data == nil {
+			configMap.Data = make(map[string]string)
+		}
+		configMap.Data[name] = strconv.FormatInt(value, 10)
+		_, err = configMaps.Update(ctx, configMap, metav1.UpdateOptions{})
+		return err
+	})
+	if err != nil {
+		return 0, errors.Wrapf(err, "error updating sequence %s", name)
+	}
+	return value, nil
+}
+
+// return the value following the current value of the named counter held in configMap
+func nextValue(configMap *corev1.ConfigMap, name string, start int64) (int64, error) {
+	v, ok := configMap.Data[name]
+	if !ok {
+		return start, nil
+	}
+	current, err := strconv.ParseInt(v, 10, 64)
+	if err != nil {
+		return 0, errors.Wrapf(err, "invalid current value of sequence %s", name)
+	}
+	return max(current+1, start), nil
+}
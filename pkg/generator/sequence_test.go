@@ -0,0 +1,131 @@
+/*
+SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company and secret-generator contributors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package generator
+
+import (
+	"context"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func TestNextSequenceValue(t *testing.T) {
+	client := fake.NewClientset()
+
+	for _, expected := range []int64{100, 101, 102} {
+		v, err := nextSequenceValue(context.TODO(), client, "testing", "tenant", 100, false)
+		if err != nil {
+			t.Fatalf("nextSequenceValue: got error: %s", err)
+		}
+		if v != expected {
+			t.Errorf("nextSequenceValue: got invalid value: %d (expected: %d)", v, expected)
+		}
+	}
+
+	v, err := nextSequenceValue(context.TODO(), client, "testing", "port", 1, false)
+	if err != nil {
+		t.Fatalf("nextSequenceValue: got error: %s", err)
+	}
+	if v != 1 {
+		t.Errorf("nextSequenceValue: got invalid value: %d (expected: 1)", v)
+	}
+
+	configMap, err := client.CoreV1().ConfigMaps("testing").Get(context.TODO(), SequenceConfigMapName, metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("nextSequenceValue: got error reading config map: %s", err)
+	}
+	if configMap.Data["tenant"] != "102" || configMap.Data["port"] != "1" {
+		t.Errorf("nextSequenceValue: got invalid config map data: %v", configMap.Data)
+	}
+}
+
+func TestNextSequenceValueWithDryRun(t *testing.T) {
+	client := fake.NewClientset()
+
+	for i := 0; i < 2; i++ {
+		v, err := nextSequenceValue(context.TODO(), client, "testing", "tenant", 100, true)
+		if err != nil {
+			t.Fatalf("nextSequenceValue: got error: %s", err)
+		}
+		if v != 100 {
+			t.Errorf("nextSequenceValue: got invalid value: %d (expected: 100)", v)
+		}
+	}
+	if _, err := client.CoreV1().ConfigMaps("testing").Get(context.TODO(), SequenceConfigMapName, metav1.GetOptions{}); !apierrors.IsNotFound(err) {
+		t.Errorf("nextSequenceValue: expected config map to be absent after dry runs, but got: %v", err)
+	}
+
+	if _, err := nextSequenceValue(context.TODO(), client, "testing", "tenant", 100, false); err != nil {
+		t.Fatalf("nextSequenceValue: got error: %s", err)
+	}
+	for i := 0; i < 2; i++ {
+		v, err := nextSequenceValue(context.TODO(), client, "testing", "tenant", 100, true)
+		if err != nil {
+			t.Fatalf("nextSequenceValue: got error: %s", err)
+		}
+		if v != 101 {
+			t.Errorf("nextSequenceValue: got invalid value: %d (expected: 101)", v)
+		}
+	}
+}
+
+func TestNextSequenceValueWithConflict(t *testing.T) {
+	client := fake.NewClientset(&corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "testing",
+			Name:      SequenceConfigMapName,
+		},
+		Data: map[string]string{
+			"tenant": "7",
+		},
+	})
+	conflicts := 0
+	client.PrependReactor("update", "configmaps", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		if conflicts < 2 {
+			conflicts++
+			return true, nil, apierrors.NewConflict(schema.GroupResource{Resource: "configmaps"}, SequenceConfigMapName, nil)
+		}
+		return false, nil, nil
+	})
+
+	v, err := nextSequenceValue(context.TODO(), client, "testing", "tenant", 1, false)
+	if err != nil {
+		t.Fatalf("nextSequenceValue: got error: %s", err)
+	}
+	if v != 8 || conflicts != 2 {
+		t.Errorf("nextSequenceValue: got invalid value: %d (expected: 8)", v)
+	}
+}
+
+func TestNextSequenceValueWithError(t *testing.T) {
+	client := fake.NewClientset(&corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "testing",
+			Name:      SequenceConfigMapName,
+		},
+		Data: map[string]string{
+			"tenant": "foo",
+		},
+	})
+
+	if _, err := nextSequenceValue(context.TODO(), client, "testing", "tenant", 1, false); err == nil {
+		t.Error("nextSequenceValue: expected error, but got none")
+	} else {
+		t.Logf("ok; got error: %s", err)
+	}
+
+	if _, err := nextSequenceValue(context.TODO(), nil, "testing", "tenant", 1, false); err == nil {
+		t.Error("nextSequenceValue: expected error, but got none")
+	} else {
+		t.Logf("ok; got error: %s", err)
+	}
+}
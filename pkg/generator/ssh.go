@@ -0,0 +1,438 @@
+/*
+SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company and secret-generator contributors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package generator
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/sha512"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"slices"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	SSHKeyPrivateKey  = "ssh-privatekey"
+	SSHKeyPublicKey   = "ssh-publickey"
+	SSHKeyCertificate = "ssh-certificate"
+)
+
+const (
+	sshCertTypeUser = 1
+	sshCertTypeHost = 2
+)
+
+// extensions granted by ssh-keygen to user certificates by default
+var sshDefaultUserExtensions = []string{
+	"permit-X11-forwarding",
+	"permit-agent-forwarding",
+	"permit-port-forwarding",
+	"permit-pty",
+	"permit-user-rc",
+}
+
+type sshOptions struct {
+	keyAlgorithm string
+	comment      string
+	ca           crypto.Signer
+	certType     uint32
+	keyId        string
+	principals   []string
+	validity     time.Duration
+	extensions   []string
+}
+
+// generate an OpenSSH key pair; if a ca is given, an OpenSSH certificate for the generated key, signed by the ca, is added
+func generateSSHBundle(options *sshOptions) (map[string]string, error) {
+	var key crypto.Signer
+	var err error
+	switch options.keyAlgorithm {
+	case "ecdsa":
+		key, err = ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	case "rsa":
+		key, err = rsa.GenerateKey(rand.Reader, 3072)
+	case "ed25519":
+		_, key, err = ed25519.GenerateKey(rand.Reader)
+	default:
+		err = fmt.Errorf("unsupported key algorithm %s", options.keyAlgorithm)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	privateKey, err := marshalSSHPrivateKey(key, options.comment)
+	if err != nil {
+		return nil, err
+	}
+	publicKeyType, publicKey, err := marshalSSHPublicKey(key.Public())
+	if err != nil {
+		return nil, err
+	}
+	values := map[string]string{
+		SSHKeyPrivateKey: privateKey,
+		SSHKeyPublicKey:  formatSSHAuthorizedKey(publicKeyType, publicKey, options.comment),
+	}
+
+	if options.ca != nil {
+		certType, cert, err := signSSHCertificate(key.Public(), options)
+		if err != nil {
+			return nil, err
+		}
+		values[SSHKeyCertificate] = formatSSHAuthorizedKey(certType, cert, options.comment)
+	}
+
+	return values, nil
+}
+
+func signSSHCertificate(publicKey crypto.PublicKey, options *sshOptions) (string, []byte, error) {
+	publicKeyType, publicKeyBlob, err := marshalSSHPublicKey(publicKey)
+	if err != nil {
+		return "", nil, err
+	}
+	_, caPublicKeyBlob, err := marshalSSHPublicKey(options.ca.Public())
+	if err != nil {
+		return "", nil, err
+	}
+	nonce := make([]byte, 32)
+	if _, err := rand.Read(nonce); err != nil {
+		return "", nil, err
+	}
+	serial := make([]byte, 8)
+	if _, err := rand.Read(serial); err != nil {
+		return "", nil, err
+	}
+	now := time.Now()
+
+	certType := publicKeyType
+	switch publicKeyType {
+	case "ssh-ed25519":
+		certType = "ssh-ed25519-cert-v01@openssh.com"
+	case "ecdsa-sha2-nistp256":
+		certType = "ecdsa-sha2-nistp256-cert-v01@openssh.com"
+	case "ssh-rsa":
+		certType = "ssh-rsa-cert-v01@openssh.com"
+	}
+
+	// the certificate embeds the public key fields (i.e. the public key blob without the leading key type)
+	publicKeyFields := sshReader(publicKeyBlob)
+	publicKeyFields.readString()
+
+	extensions := slices.Clone(options.extensions)
+	slices.Sort(extensions)
+	var packedExtensions sshWriter
+	for _, extension := range slices.Compact(extensions) {
+		packedExtensions.writeString([]byte(extension))
+		packedExtensions.writeString(nil)
+	}
+	var packedPrincipals sshWriter
+	for _, principal := range options.principals {
+		packedPrincipals.writeString([]byte(principal))
+	}
+
+	var cert sshWriter
+	cert.writeString([]byte(certType))
+	cert.writeString(nonce)
+	cert.Write(publicKeyFields)
+	cert.Write(serial)
+	cert.writeUint32(options.certType)
+	cert.writeString([]byte(options.keyId))
+	cert.writeString(packedPrincipals.Bytes())
+	cert.writeUint64(uint64(now.Add(-5 * time.Minute).Unix()))
+	cert.writeUint64(uint64(now.Add(options.validity).Unix()))
+	// critical options
+	cert.writeString(nil)
+	cert.writeString(packedExtensions.Bytes())
+	// reserved
+	cert.writeString(nil)
+	cert.writeString(caPublicKeyBlob)
+	signature, err := signSSH(options.ca, cert.Bytes())
+	if err != nil {
+		return "", nil, err
+	}
+	cert.writeString(signature)
+
+	return certType, cert.Bytes(), nil
+}
+
+func signSSH(signer crypto.Signer, data []byte) ([]byte, error) {
+	var signature sshWriter
+	switch signer.Public().(type) {
+	case ed25519.PublicKey:
+		blob, err := signer.Sign(rand.Reader, data, crypto.Hash(0))
+		if err != nil {
+			return nil, err
+		}
+		signature.writeString([]byte("ssh-ed25519"))
+		signature.writeString(blob)
+	case *ecdsa.PublicKey:
+		digest := sha256.Sum256(data)
+		r, s, err := ecdsa.Sign(rand.Reader, signer.(*ecdsa.PrivateKey), digest[:])
+		if err != nil {
+			return nil, err
+		}
+		var blob sshWriter
+		blob.writeMPInt(r)
+		blob.writeMPInt(s)
+		signature.writeString([]byte("ecdsa-sha2-nistp256"))
+		signature.writeString(blob.Bytes())
+	case *rsa.PublicKey:
+		digest := sha512.Sum512(data)
+		blob, err := signer.Sign(rand.Reader, digest[:], crypto.SHA512)
+		if err != nil {
+			return nil, err
+		}
+		signature.writeString([]byte("rsa-sha2-512"))
+		signature.writeString(blob)
+	default:
+		return nil, fmt.Errorf("unsupported ssh ca key type %T", signer.Public())
+	}
+	return signature.Bytes(), nil
+}
+
+func marshalSSHPublicKey(publicKey crypto.PublicKey) (string, []byte, error) {
+	var blob sshWriter
+	var keyType string
+	switch publicKey := publicKey.(type) {
+	case ed25519.PublicKey:
+		keyType = "ssh-ed25519"
+		blob.writeString([]byte(keyType))
+		blob.writeString(publicKey)
+	case *ecdsa.PublicKey:
+		if publicKey.Curve != elliptic.P256() {
+			return "", nil, fmt.Errorf("unsupported ecdsa curve %s", publicKey.Curve.Params().Name)
+		}
+		point, err := publicKey.Bytes()
+		if err != nil {
+			return "", nil, err
+		}
+		keyType = "ecdsa-sha2-nistp256"
+		blob.writeString([]byte(keyType))
+		blob.writeString([]byte("nistp256"))
+		blob.writeString(point)
+	case *rsa.PublicKey:
+		keyType = "ssh-rsa"
+		blob.writeString([]byte(keyType))
+		blob.writeMPInt(big.NewInt(int64(publicKey.E)))
+		blob.writeMPInt(publicKey.N)
+	default:
+		return "", nil, fmt.Errorf("unsupported ssh key type %T", publicKey)
+	}
+	return keyType, blob.Bytes(), nil
+}
+
+// marshal private key in the (unencrypted) openssh-key-v1 format, as written by ssh-keygen
+func marshalSSHPrivateKey(key crypto.Signer, comment string) (string, error) {
+	keyType, publicKeyBlob, err := marshalSSHPublicKey(key.Public())
+	if err != nil {
+		return "", err
+	}
+	check := make([]byte, 4)
+	if _, err := rand.Read(check); err != nil {
+		return "", err
+	}
+
+	var private sshWriter
+	private.Write(check)
+	private.Write(check)
+	private.writeString([]byte(keyType))
+	switch key := key.(type) {
+	case ed25519.PrivateKey:
+		private.writeString(key.Public().(ed25519.PublicKey))
+		private.writeString(key)
+	case *ecdsa.PrivateKey:
+		point, err := key.PublicKey.Bytes()
+		if err != nil {
+			return "", err
+		}
+		private.writeString([]byte("nistp256"))
+		private.writeString(point)
+		private.writeMPInt(key.D)
+	case *rsa.PrivateKey:
+		private.writeMPInt(key.N)
+		private.writeMPInt(big.NewInt(int64(key.E)))
+		private.writeMPInt(key.D)
+		private.writeMPInt(key.Precomputed.Qinv)
+		private.writeMPInt(key.Primes[0])
+		private.writeMPInt(key.Primes[1])
+	}
+	private.writeString([]byte(comment))
+	for i := 1; private.Len()%8 != 0; i++ {
+		private.WriteByte(byte(i))
+	}
+
+	var buf sshWriter
+	buf.WriteString("openssh-key-v1\x00")
+	// cipher, kdf, kdf options
+	buf.writeString([]byte("none"))
+	buf.writeString([]byte("none"))
+	buf.writeString(nil)
+	buf.writeUint32(1)
+	buf.writeString(publicKeyBlob)
+	buf.writeString(private.Bytes())
+
+	return string(pem.EncodeToMemory(&pem.Block{Type: "OPENSSH PRIVATE KEY", Bytes: buf.Bytes()})), nil
+}
+
+// parse a PEM encoded private key; supported are unencrypted OpenSSH, PKCS#8, PKCS#1 and SEC 1 keys
+func parseSSHPrivateKey(data []byte) (crypto.Signer, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("no PEM encoded private key found")
+	}
+	var key any
+	var err error
+	switch block.Type {
+	case "OPENSSH PRIVATE KEY":
+		key, err = parseOpenSSHPrivateKey(block.Bytes)
+	case "PRIVATE KEY":
+		key, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	case "RSA PRIVATE KEY":
+		key, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "EC PRIVATE KEY":
+		key, err = x509.ParseECPrivateKey(block.Bytes)
+	default:
+		err = fmt.Errorf("unsupported PEM block type %s", block.Type)
+	}
+	if err != nil {
+		return nil, err
+	}
+	signer, ok := key.(crypto.Signer)
+	if !ok {
+		return nil, fmt.Errorf("unsupported private key type %T", key)
+	}
+	if _, _, err := marshalSSHPublicKey(signer.Public()); err != nil {
+		return nil, err
+	}
+	return signer, nil
+}
+
+func parseOpenSSHPrivateKey(data []byte) (key crypto.Signer, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			key = nil
+			err = fmt.Errorf("invalid openssh private key")
+		}
+	}()
+
+	magic := []byte("openssh-key-v1\x00")
+	if !bytes.HasPrefix(data, magic) {
+		return nil, fmt.Errorf("invalid openssh private key")
+	}
+	r := sshReader(data[len(magic):])
+	if cipher := string(r.readString()); cipher != "none" {
+		return nil, fmt.Errorf("encrypted openssh private keys are not supported")
+	}
+	r.readString()
+	r.readString()
+	if n := r.readUint32(); n != 1 {
+		return nil, fmt.Errorf("openssh private key files with multiple keys are not supported")
+	}
+	r.readString()
+	private := sshReader(r.readString())
+	if private.readUint32() != private.readUint32() {
+		return nil, fmt.Errorf("invalid openssh private key")
+	}
+	switch keyType := string(private.readString()); keyType {
+	case "ssh-ed25519":
+		private.readString()
+		key := private.readString()
+		if len(key) != ed25519.PrivateKeySize {
+			return nil, fmt.Errorf("invalid openssh private key")
+		}
+		return ed25519.PrivateKey(key), nil
+	case "ecdsa-sha2-nistp256":
+		private.readString()
+		private.readString()
+		return ecdsa.ParseRawPrivateKey(elliptic.P256(), private.readMPInt().FillBytes(make([]byte, 32)))
+	case "ssh-rsa":
+		key := &rsa.PrivateKey{}
+		key.N = private.readMPInt()
+		key.E = int(private.readMPInt().Int64())
+		key.D = private.readMPInt()
+		private.readMPInt()
+		key.Primes = []*big.Int{private.readMPInt(), private.readMPInt()}
+		if err := key.Validate(); err != nil {
+			return nil, err
+		}
+		key.Precompute()
+		return key, nil
+	default:
+		return nil, fmt.Errorf("unsupported openssh key type %s", keyType)
+	}
+}
+
+func formatSSHAuthorizedKey(keyType string, blob []byte, comment string) string {
+	s := keyType + " " + base64.StdEncoding.EncodeToString(blob)
+	if comment != "" {
+		s += " " + comment
+	}
+	return s + "\n"
+}
+
+// writer for the SSH wire format (RFC 4251)
+type sshWriter struct {
+	bytes.Buffer
+}
+
+func (w *sshWriter) writeUint32(v uint32) {
+	w.Write(binary.BigEndian.AppendUint32(nil, v))
+}
+
+func (w *sshWriter) writeUint64(v uint64) {
+	w.Write(binary.BigEndian.AppendUint64(nil, v))
+}
+
+func (w *sshWriter) writeString(s []byte) {
+	w.writeUint32(uint32(len(s)))
+	w.Write(s)
+}
+
+func (w *sshWriter) writeMPInt(v *big.Int) {
+	b := v.Bytes()
+	if len(b) > 0 && b[0]&0x80 != 0 {
+		b = append([]byte{0}, b...)
+	}
+	w.writeString(b)
+}
+
+// reader for the SSH wire format (RFC 4251); panics on malformed input
+type sshReader []byte
+
+func (r *sshReader) readUint32() uint32 {
+	if len(*r) < 4 {
+		panic(errors.New("unexpected end of data"))
+	}
+	v := binary.BigEndian.Uint32(*r)
+	*r = (*r)[4:]
+	return v
+}
+
+func (r *sshReader) readString() []byte {
+	n := r.readUint32()
+	if uint32(len(*r)) < n {
+		panic(errors.New("unexpected end of data"))
+	}
+	s := (*r)[:n]
+	*r = (*r)[n:]
+	return s
+}
+
+func (r *sshReader) readMPInt() *big.Int {
+	return new(big.Int).SetBytes(r.readString())
+}
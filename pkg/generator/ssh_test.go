@@ -0,0 +1,176 @@
+/*
+SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company and secret-generator contributors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package generator
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/sha512"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"math/big"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestGenerateSSHBundle(t *testing.T) {
+	for _, keyAlgorithm := range []string{"ed25519", "ecdsa", "rsa"} {
+		values, err := generateSSHBundle(&sshOptions{keyAlgorithm: keyAlgorithm, comment: "test"})
+		if err != nil {
+			t.Fatalf("generateSSHBundle: got error: %s", err)
+		}
+		if _, ok := values[SSHKeyCertificate]; ok {
+			t.Errorf("generateSSHBundle: got unexpected certificate")
+		}
+		key, err := parseSSHPrivateKey([]byte(values[SSHKeyPrivateKey]))
+		if err != nil {
+			t.Fatalf("generateSSHBundle: got invalid private key; error: %s", err)
+		}
+		keyType, publicKey, err := marshalSSHPublicKey(key.Public())
+		if err != nil {
+			t.Fatalf("generateSSHBundle: got invalid private key; error: %s", err)
+		}
+		if s := formatSSHAuthorizedKey(keyType, publicKey, "test"); s != values[SSHKeyPublicKey] {
+			t.Errorf("generateSSHBundle: got non-matching public key: %s", values[SSHKeyPublicKey])
+		}
+	}
+}
+
+func TestGenerateSSHBundleWithCertificate(t *testing.T) {
+	_, ed25519CA, _ := ed25519.GenerateKey(rand.Reader)
+	ecdsaCA, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	rsaCA, _ := rsa.GenerateKey(rand.Reader, 2048)
+
+	for _, ca := range []crypto.Signer{ed25519CA, ecdsaCA, rsaCA} {
+		values, err := generateSSHBundle(&sshOptions{
+			keyAlgorithm: "ed25519",
+			ca:           ca,
+			certType:     sshCertTypeUser,
+			keyId:        "test",
+			principals:   []string{"alice", "bob"},
+			validity:     time.Hour,
+			extensions:   []string{"permit-pty", "permit-agent-forwarding"},
+		})
+		if err != nil {
+			t.Fatalf("generateSSHBundle: got error: %s", err)
+		}
+		fields := strings.Fields(values[SSHKeyCertificate])
+		if len(fields) != 2 || fields[0] != "ssh-ed25519-cert-v01@openssh.com" {
+			t.Fatalf("generateSSHBundle: got invalid certificate: %s", values[SSHKeyCertificate])
+		}
+		blob, err := base64.StdEncoding.DecodeString(fields[1])
+		if err != nil {
+			t.Fatalf("generateSSHBundle: got invalid certificate; error: %s", err)
+		}
+
+		r := sshReader(blob)
+		r.readString()
+		r.readString()
+		r.readString()
+		r.readUint32()
+		r.readUint32()
+		if certType := r.readUint32(); certType != sshCertTypeUser {
+			t.Errorf("generateSSHBundle: got invalid certificate type: %d", certType)
+		}
+		if keyId := string(r.readString()); keyId != "test" {
+			t.Errorf("generateSSHBundle: got invalid key id: %s", keyId)
+		}
+		packedPrincipals := sshReader(r.readString())
+		var principals []string
+		for len(packedPrincipals) > 0 {
+			principals = append(principals, string(packedPrincipals.readString()))
+		}
+		if !slices.Equal(principals, []string{"alice", "bob"}) {
+			t.Errorf("generateSSHBundle: got invalid principals: %v", principals)
+		}
+		r.readUint32()
+		r.readUint32()
+		r.readUint32()
+		r.readUint32()
+		r.readString()
+		packedExtensions := sshReader(r.readString())
+		var extensions []string
+		for len(packedExtensions) > 0 {
+			extensions = append(extensions, string(packedExtensions.readString()))
+			packedExtensions.readString()
+		}
+		if !slices.Equal(extensions, []string{"permit-agent-forwarding", "permit-pty"}) {
+			t.Errorf("generateSSHBundle: got invalid extensions: %v", extensions)
+		}
+		r.readString()
+		r.readString()
+		signed := blob[:len(blob)-len(r)]
+		signature := sshReader(r.readString())
+		signatureType := string(signature.readString())
+		signatureBlob := signature.readString()
+		valid := false
+		switch ca := ca.Public().(type) {
+		case ed25519.PublicKey:
+			valid = signatureType == "ssh-ed25519" && ed25519.Verify(ca, signed, signatureBlob)
+		case *ecdsa.PublicKey:
+			digest := sha256.Sum256(signed)
+			rs := sshReader(signatureBlob)
+			valid = signatureType == "ecdsa-sha2-nistp256" && ecdsa.Verify(ca, digest[:], rs.readMPInt(), rs.readMPInt())
+		case *rsa.PublicKey:
+			digest := sha512.Sum512(signed)
+			valid = signatureType == "rsa-sha2-512" && rsa.VerifyPKCS1v15(ca, crypto.SHA512, digest[:], signatureBlob) == nil
+		}
+		if !valid {
+			t.Errorf("generateSSHBundle: got invalid certificate signature (ca: %T)", ca)
+		}
+	}
+}
+
+func TestParseSSHPrivateKey(t *testing.T) {
+	rsaKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+	der, _ := x509.MarshalPKCS8PrivateKey(rsaKey)
+	key, err := parseSSHPrivateKey(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}))
+	if err != nil {
+		t.Fatalf("parseSSHPrivateKey: got error: %s", err)
+	}
+	if !rsaKey.Equal(key) {
+		t.Errorf("parseSSHPrivateKey: got different key")
+	}
+
+	openSSHKey, _ := marshalSSHPrivateKey(rsaKey, "")
+	key, err = parseSSHPrivateKey([]byte(openSSHKey))
+	if err != nil {
+		t.Fatalf("parseSSHPrivateKey: got error: %s", err)
+	}
+	if !rsaKey.Equal(key) {
+		t.Errorf("parseSSHPrivateKey: got different key")
+	}
+
+	ecdsaKey, _ := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	der, _ = x509.MarshalECPrivateKey(ecdsaKey)
+	if _, err := parseSSHPrivateKey(pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: der})); err == nil {
+		t.Error("parseSSHPrivateKey: expected error, but got none")
+	} else {
+		t.Logf("ok; got error: %s", err)
+	}
+
+	if _, err := parseSSHPrivateKey(pem.EncodeToMemory(&pem.Block{Type: "OPENSSH PRIVATE KEY", Bytes: []byte("openssh-key-v1\x00\x00\x00")})); err == nil {
+		t.Error("parseSSHPrivateKey: expected error, but got none")
+	} else {
+		t.Logf("ok; got error: %s", err)
+	}
+}
+
+func TestWriteMPInt(t *testing.T) {
+	var w sshWriter
+	w.writeMPInt(big.NewInt(0x80))
+	if b := w.Bytes(); !slices.Equal(b, []byte{0, 0, 0, 2, 0, 0x80}) {
+		t.Errorf("writeMPInt: got invalid encoding: %v", b)
+	}
+}
@@ -0,0 +1,103 @@
+/*
+SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company and secret-generator contributors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package generator
+
+import (
+	"fmt"
+	"io"
+)
+
+var usernameAdjectives = []string{
+	"able", "amber", "azure", "bold", "brave", "brisk", "calm", "clever", "cosmic", "crisp",
+	"daring", "eager", "early", "fancy", "fast", "gentle", "golden", "grand", "happy", "hidden",
+	"humble", "jolly", "keen", "kind", "lively", "lucky", "mellow", "merry", "mighty", "modest",
+	"noble", "polite", "proud", "quick", "quiet", "rapid", "silent", "silver", "smart", "snowy",
+	"solar", "steady", "sunny", "swift", "tidy", "vivid", "warm", "wise", "witty", "young",
+}
+
+var usernameNouns = []string{
+	"badger", "beacon", "bison", "cedar", "comet", "coral", "crane", "delta", "eagle", "falcon",
+	"fern", "fjord", "forest", "fox", "galaxy", "glacier", "harbor", "hawk", "heron", "island",
+	"jaguar", "lagoon", "lake", "lynx", "maple", "meadow", "meteor", "moose", "nebula", "oak",
+	"orbit", "otter", "owl", "panda", "pine", "planet", "prairie", "quartz", "raven", "reef",
+	"river", "robin", "summit", "tiger", "tundra", "valley", "walrus", "willow", "wolf", "zephyr",
+}
+
+type usernameOptions struct {
+	style     string
+	prefix    string
+	length    int
+	maxLength int
+	alphabet  string
+	separator string
+	digits    int
+}
+
+// generate an identifier, such as a database user name; with default options, the result starts with a lower case letter,
+// and contains only lower case letters, digits and the separator (i.e. it is a valid RFC 1123 label if separator is -);
+// a prefix is joined to the generated part with the separator, and counts towards length and maximum length
+func generateUsername(random io.Reader, options *usernameOptions) (string, error) {
+	prefix := ""
+	if options.prefix != "" {
+		prefix = options.prefix + options.separator
+	}
+	if len(prefix) >= options.maxLength {
+		return "", fmt.Errorf("prefix %s does not fit into maximum length %d", prefix, options.maxLength)
+	}
+	switch options.style {
+	case "random":
+		if options.length > options.maxLength {
+			return "", fmt.Errorf("length %d exceeds maximum length %d", options.length, options.maxLength)
+		}
+		n := options.length - len(prefix)
+		if n < 1 {
+			return "", fmt.Errorf("length %d does not exceed length of prefix %s", options.length, prefix)
+		}
+		value := prefix
+		if value == "" {
+			first, err := randomString(random, alphabetLower, 1)
+			if err != nil {
+				return "", err
+			}
+			value = first
+			n--
+		}
+		rest, err := randomString(random, options.alphabet, n)
+		if err != nil {
+			return "", err
+		}
+		return value + rest, nil
+	case "words":
+		suffix := ""
+		if options.digits > 0 {
+			digits, err := randomString(random, alphabetDigits, options.digits)
+			if err != nil {
+				return "", err
+			}
+			suffix = options.separator + digits
+		}
+		// pick uniformly from all adjective-noun combinations fitting into the maximum length
+		var candidates []string
+		for _, adjective := range usernameAdjectives {
+			for _, noun := range usernameNouns {
+				value := prefix + adjective + options.separator + noun + suffix
+				if len(value) <= options.maxLength {
+					candidates = append(candidates, value)
+				}
+			}
+		}
+		if len(candidates) == 0 {
+			return "", fmt.Errorf("unable to generate words fitting into maximum length %d", options.maxLength)
+		}
+		value, err := randomChoice(random, candidates, 1)
+		if err != nil {
+			return "", err
+		}
+		return value[0], nil
+	default:
+		return "", fmt.Errorf("unsupported username style %s", options.style)
+	}
+}
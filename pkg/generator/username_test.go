@@ -0,0 +1,69 @@
+/*
+SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company and secret-generator contributors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package generator
+
+import (
+	"crypto/rand"
+	"regexp"
+	"testing"
+)
+
+func TestGenerateUsername(t *testing.T) {
+	v, err := generateUsername(rand.Reader, &usernameOptions{style: "random", length: 16, maxLength: 63, alphabet: alphabetLower + alphabetDigits})
+	if err != nil {
+		t.Fatalf("generateUsername: got error: %s", err)
+	}
+	if !regexp.MustCompile(`^[a-z][a-z0-9]{15}$`).MatchString(v) {
+		t.Errorf("generateUsername: got invalid username: %s", v)
+	}
+
+	v, err = generateUsername(rand.Reader, &usernameOptions{style: "random", prefix: "app", length: 32, maxLength: 32, alphabet: alphabetLower, separator: "-"})
+	if err != nil {
+		t.Fatalf("generateUsername: got error: %s", err)
+	}
+	if !regexp.MustCompile(`^app-[a-z]{28}$`).MatchString(v) {
+		t.Errorf("generateUsername: got invalid username: %s", v)
+	}
+
+	v, err = generateUsername(rand.Reader, &usernameOptions{style: "words", maxLength: 63, separator: "-", digits: 3})
+	if err != nil {
+		t.Fatalf("generateUsername: got error: %s", err)
+	}
+	if !regexp.MustCompile(`^[a-z]+-[a-z]+-[0-9]{3}$`).MatchString(v) {
+		t.Errorf("generateUsername: got invalid username: %s", v)
+	}
+
+	v, err = generateUsername(rand.Reader, &usernameOptions{style: "words", prefix: "db", maxLength: 12, separator: "_"})
+	if err != nil {
+		t.Fatalf("generateUsername: got error: %s", err)
+	}
+	if !regexp.MustCompile(`^db_[a-z]+_[a-z]+$`).MatchString(v) || len(v) > 12 {
+		t.Errorf("generateUsername: got invalid username: %s", v)
+	}
+}
+
+func TestGenerateUsernameWithError(t *testing.T) {
+	if _, err := generateUsername(rand.Reader, &usernameOptions{style: "random", length: 40, maxLength: 32, alphabet: alphabetLower}); err == nil {
+		t.Error("generateUsername: expected error, but got none")
+	}
+
+	if _, err := generateUsername(rand.Reader, &usernameOptions{style: "random", prefix: "app", length: 4, maxLength: 32, alphabet: alphabetLower, separator: "-"}); err == nil {
+		t.Error("generateUsername: expected error, but got none")
+	}
+
+	// prefix and separator must leave room for the generated part
+	if _, err := generateUsername(rand.Reader, &usernameOptions{style: "random", prefix: "app", length: 4, maxLength: 4, alphabet: alphabetLower, separator: "-"}); err == nil {
+		t.Error("generateUsername: expected error, but got none")
+	}
+
+	if _, err := generateUsername(rand.Reader, &usernameOptions{style: "words", prefix: "application", maxLength: 16, separator: "-"}); err == nil {
+		t.Error("generateUsername: expected error, but got none")
+	}
+
+	if _, err := generateUsername(rand.Reader, &usernameOptions{style: "words", maxLength: 5, separator: "-"}); err == nil {
+		t.Error("generateUsername: expected error, but got none")
+	}
+}
@@ -0,0 +1,83 @@
+/*
+SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company and secret-generator contributors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package generator
+
+import (
+	"crypto/rand"
+	"fmt"
+	"io"
+	"math/big"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+func normalizeSymbols(symbols string) string {
+	var l []rune
+	var i = 0
+	for _, r := range Symbols + extraSymbols {
+		if strings.ContainsRune(symbols, r) {
+			l = append(l, r)
+			i++
+		}
+	}
+	return string(l)
+}
+
+// ParseDuration parses a duration; in addition to the formats understood by time.ParseDuration(), a number of days (e.g. 90d) is accepted.
+func ParseDuration(s string) (time.Duration, error) {
+	if m := regexp.MustCompile(`^([+-]?\d+)d$`).FindStringSubmatch(s); m != nil {
+		days, err := strconv.Atoi(m[1])
+		if err != nil {
+			return 0, err
+		}
+		return time.Duration(days) * 24 * time.Hour, nil
+	}
+	return time.ParseDuration(s)
+}
+
+// return a string of given length, with characters picked uniformly from alphabet
+func randomString(random io.Reader, alphabet string, length int) (string, error) {
+	runes := []rune(alphabet)
+	limit := big.NewInt(int64(len(runes)))
+	value := make([]rune, length)
+	for i := range value {
+		n, err := rand.Int(random, limit)
+		if err != nil {
+			return "", err
+		}
+		value[i] = runes[n.Int64()]
+	}
+	return string(value), nil
+}
+
+// return given number of random bytes, encoded by the given function
+func randomEncodedBytes(random io.Reader, n int, encode func([]byte) string) (string, error) {
+	value := make([]byte, n)
+	if _, err := io.ReadFull(random, value); err != nil {
+		return "", err
+	}
+	return encode(value), nil
+}
+
+// return count distinct elements (by position) of values, picked uniformly at random
+func randomChoice(random io.Reader, values []string, count int) ([]string, error) {
+	if count < 1 || count > len(values) {
+		return nil, fmt.Errorf("unable to pick %d out of %d values", count, len(values))
+	}
+	values = slices.Clone(values)
+	for i := 0; i < count; i++ {
+		n, err := rand.Int(random, big.NewInt(int64(len(values)-i)))
+		if err != nil {
+			return nil, err
+		}
+		j := i + int(n.Int64())
+		values[i], values[j] = values[j], values[i]
+	}
+	return values[:count], nil
+}
@@ -0,0 +1,77 @@
+/*
+SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company and secret-generator contributors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package generator
+
+import (
+	"crypto/rand"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNormalizeSymbols(t *testing.T) {
+	if symbols := normalizeSymbols(Symbols); symbols != Symbols {
+		t.Error("normalizeSymbols: got invalid symbols")
+	}
+
+	if symbols := normalizeSymbols("_+-_+-"); symbols != "-_+" {
+		t.Error("normalizeSymbols: got invalid symbols")
+	}
+}
+
+func TestParseDuration(t *testing.T) {
+	if d, err := ParseDuration("90d"); err != nil || d != 90*24*time.Hour {
+		t.Errorf("ParseDuration: got invalid duration: %s (error: %v)", d, err)
+	}
+
+	if d, err := ParseDuration("-1d"); err != nil || d != -24*time.Hour {
+		t.Errorf("ParseDuration: got invalid duration: %s (error: %v)", d, err)
+	}
+
+	if d, err := ParseDuration("1h30m"); err != nil || d != 90*time.Minute {
+		t.Errorf("ParseDuration: got invalid duration: %s (error: %v)", d, err)
+	}
+
+	if _, err := ParseDuration("foo"); err == nil {
+		t.Error("ParseDuration: expected error, but got none")
+	}
+}
+
+func TestRandomString(t *testing.T) {
+	s, err := randomString(rand.Reader, "ab€", 100)
+	if err != nil {
+		t.Fatalf("randomString: got error: %s", err)
+	}
+	if len([]rune(s)) != 100 || strings.Trim(s, "ab€") != "" {
+		t.Errorf("randomString: got invalid string: %s", s)
+	}
+}
+
+func TestRandomChoice(t *testing.T) {
+	values := []string{"a", "b", "c", "d"}
+	for i := 0; i < 100; i++ {
+		choices, err := randomChoice(rand.Reader, values, 3)
+		if err != nil {
+			t.Fatalf("randomChoice: got error: %s", err)
+		}
+		if len(choices) != 3 || len(slices.Compact(slices.Sorted(slices.Values(choices)))) != 3 {
+			t.Errorf("randomChoice: got invalid choices: %v", choices)
+		}
+		for _, choice := range choices {
+			if !slices.Contains(values, choice) {
+				t.Errorf("randomChoice: got invalid choices: %v", choices)
+			}
+		}
+	}
+	if !slices.Equal(values, []string{"a", "b", "c", "d"}) {
+		t.Errorf("randomChoice: input values got modified")
+	}
+
+	if _, err := randomChoice(rand.Reader, values, 5); err == nil {
+		t.Error("randomChoice: expected error, but got none")
+	}
+}
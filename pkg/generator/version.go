@@ -0,0 +1,39 @@
+/*
+SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company and secret-generator contributors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package generator
+
+import (
+	"runtime/debug"
+)
+
+const modulePath = "github.com/sap/secret-generator"
+
+// version of the generators, as set at build time, e.g. by -ldflags "-X github.com/sap/secret-generator/pkg/generator.version=v1.0.0"
+var version string
+
+// Version returns the version of the generators, i.e. of the secret-generator module; unless set at build time, it is taken from
+// the build information of the running program (which also works if the module is used as a library); "(devel)" means unknown.
+func Version() string {
+	if version != "" {
+		return version
+	}
+	if info, ok := debug.ReadBuildInfo(); ok {
+		if info.Main.Path == modulePath && info.Main.Version != "" {
+			return info.Main.Version
+		}
+		for _, dep := range info.Deps {
+			if dep.Path == modulePath {
+				if dep.Replace != nil {
+					dep = dep.Replace
+				}
+				if dep.Version != "" {
+					return dep.Version
+				}
+			}
+		}
+	}
+	return "(devel)"
+}
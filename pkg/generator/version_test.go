@@ -0,0 +1,21 @@
+/*
+SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company and secret-generator contributors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package generator
+
+import (
+	"testing"
+)
+
+func TestVersion(t *testing.T) {
+	if v := Version(); v == "" {
+		t.Error("got empty version")
+	}
+	version = "v1.2.3"
+	defer func() { version = "" }()
+	if v := Version(); v != "v1.2.3" {
+		t.Errorf("got invalid version: %s", v)
+	}
+}
@@ -0,0 +1,130 @@
+/*
+SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company and secret-generator contributors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"github.com/pkg/errors"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+// NewMutatingHandler returns an http.Handler serving the mutating admission endpoint (for secrets) of w; the admission request
+// is passed to w in the context (see NewContextWithRequest()).
+func NewMutatingHandler(w *SecretWebhook) http.Handler {
+	return &admission.Webhook{Handler: &mutatingHandler{webhook: w, decoder: newDecoder()}}
+}
+
+// NewValidatingHandler returns an http.Handler serving the validating admission endpoint (for secrets) of w.
+func NewValidatingHandler(w *SecretWebhook) http.Handler {
+	return &admission.Webhook{Handler: &validatingHandler{webhook: w, decoder: newDecoder()}}
+}
+
+type mutatingHandler struct {
+	webhook *SecretWebhook
+	decoder admission.Decoder
+}
+
+func (h *mutatingHandler) Handle(ctx context.Context, req admission.Request) admission.Response {
+	if req.Operation != admissionv1.Create && req.Operation != admissionv1.Update {
+		return admission.Allowed("")
+	}
+	ctx, warnings := newContextWithWarnings(NewContextWithRequest(ctx, &req.AdmissionRequest))
+	return h.handle(ctx, req).WithWarnings(warnings.messages...)
+}
+
+func (h *mutatingHandler) handle(ctx context.Context, req admission.Request) admission.Response {
+	secret := &corev1.Secret{}
+	if err := h.decoder.Decode(req, secret); err != nil {
+		return admission.Errored(http.StatusBadRequest, err)
+	}
+	switch req.Operation {
+	case admissionv1.Create:
+		if err := h.webhook.MutateCreate(ctx, secret); err != nil {
+			return errorResponse(err)
+		}
+	case admissionv1.Update:
+		oldSecret := &corev1.Secret{}
+		if err := h.decoder.DecodeRaw(req.OldObject, oldSecret); err != nil {
+			return admission.Errored(http.StatusBadRequest, err)
+		}
+		if err := h.webhook.MutateUpdate(ctx, oldSecret, secret); err != nil {
+			return errorResponse(err)
+		}
+	}
+	raw, err := json.Marshal(secret)
+	if err != nil {
+		return admission.Errored(http.StatusInternalServerError, err)
+	}
+	return admission.PatchResponseFromRaw(req.Object.Raw, raw)
+}
+
+type validatingHandler struct {
+	webhook *SecretWebhook
+	decoder admission.Decoder
+}
+
+func (h *validatingHandler) Handle(ctx context.Context, req admission.Request) admission.Response {
+	ctx, warnings := newContextWithWarnings(NewContextWithRequest(ctx, &req.AdmissionRequest))
+	return h.handle(ctx, req).WithWarnings(warnings.messages...)
+}
+
+func (h *validatingHandler) handle(ctx context.Context, req admission.Request) admission.Response {
+	secret := &corev1.Secret{}
+	var err error
+	switch req.Operation {
+	case admissionv1.Create:
+		if err := h.decoder.Decode(req, secret); err != nil {
+			return admission.Errored(http.StatusBadRequest, err)
+		}
+		err = h.webhook.ValidateCreate(ctx, secret)
+	case admissionv1.Update:
+		oldSecret := &corev1.Secret{}
+		if err := h.decoder.Decode(req, secret); err != nil {
+			return admission.Errored(http.StatusBadRequest, err)
+		}
+		if err := h.decoder.DecodeRaw(req.OldObject, oldSecret); err != nil {
+			return admission.Errored(http.StatusBadRequest, err)
+		}
+		err = h.webhook.ValidateUpdate(ctx, oldSecret, secret)
+	case admissionv1.Delete:
+		if err := h.decoder.DecodeRaw(req.OldObject, secret); err != nil {
+			return admission.Errored(http.StatusBadRequest, err)
+		}
+		err = h.webhook.ValidateDelete(ctx, secret)
+	}
+	if err != nil {
+		return errorResponse(err)
+	}
+	return admission.Allowed("")
+}
+
+func newDecoder() admission.Decoder {
+	scheme := runtime.NewScheme()
+	if err := corev1.AddToScheme(scheme); err != nil {
+		// cannot happen
+		panic(err)
+	}
+	return admission.NewDecoder(scheme)
+}
+
+// return a response denying the admission request because of err; api errors (such as invalid errors with their causes)
+// are passed through, other errors deny the request with their message
+func errorResponse(err error) admission.Response {
+	var status apierrors.APIStatus
+	if errors.As(err, &status) {
+		result := status.Status()
+		return admission.Response{AdmissionResponse: admissionv1.AdmissionResponse{Allowed: false, Result: &result}}
+	}
+	return admission.Denied(err.Error())
+}
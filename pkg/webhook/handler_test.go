@@ -0,0 +1,134 @@
+/*
+SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company and secret-generator contributors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/sap/secret-generator/pkg/generator"
+)
+
+// send an admission review for secret (and oldSecret, if not nil) to handler, and return the admission response
+func review(t *testing.T, handler http.Handler, operation admissionv1.Operation, secret *corev1.Secret, oldSecret *corev1.Secret, dryRun bool) *admissionv1.AdmissionResponse {
+	req := &admissionv1.AdmissionRequest{
+		UID:       "test",
+		Kind:      metav1.GroupVersionKind{Version: "v1", Kind: "Secret"},
+		Operation: operation,
+		DryRun:    &dryRun,
+	}
+	if secret != nil {
+		raw, err := json.Marshal(secret)
+		if err != nil {
+			t.Fatalf("got error: %s", err)
+		}
+		req.Object = runtime.RawExtension{Raw: raw}
+	}
+	if oldSecret != nil {
+		raw, err := json.Marshal(oldSecret)
+		if err != nil {
+			t.Fatalf("got error: %s", err)
+		}
+		req.OldObject = runtime.RawExtension{Raw: raw}
+	}
+	body, err := json.Marshal(&admissionv1.AdmissionReview{
+		TypeMeta: metav1.TypeMeta{APIVersion: "admission.k8s.io/v1", Kind: "AdmissionReview"},
+		Request:  req,
+	})
+	if err != nil {
+		t.Fatalf("got error: %s", err)
+	}
+	httpReq := httptest.NewRequest(http.MethodPost, "/", bytes.NewReader(body))
+	httpReq.Header.Set("Content-Type", "application/json")
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httpReq)
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("got http status %d: %s", recorder.Code, recorder.Body.String())
+	}
+	review := &admissionv1.AdmissionReview{}
+	if err := json.Unmarshal(recorder.Body.Bytes(), review); err != nil {
+		t.Fatalf("got error: %s", err)
+	}
+	if review.Response == nil || review.Response.UID != "test" {
+		t.Fatalf("got invalid admission response: %v", review.Response)
+	}
+	return review.Response
+}
+
+func TestMutatingHandler(t *testing.T) {
+	client := fake.NewClientset()
+	handler := NewMutatingHandler(NewSecretWebhook(WithClient(client), WithSequenceNamespace("system")))
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "testing",
+			Name:      "test",
+		},
+		Data: map[string][]byte{
+			"tenant": []byte("%generate:sequence:name=tenant"),
+		},
+	}
+
+	// dry runs do not consume sequence values
+	for _, dryRun := range []bool{true, true, false} {
+		resp := review(t, handler, admissionv1.Create, secret, nil, dryRun)
+		if !resp.Allowed || len(resp.Patch) == 0 {
+			t.Errorf("got invalid admission response: %v", resp)
+		}
+		_, err := client.CoreV1().ConfigMaps("system").Get(context.TODO(), generator.SequenceConfigMapName, metav1.GetOptions{})
+		if dryRun && !apierrors.IsNotFound(err) {
+			t.Errorf("expected no sequence config map after dry run, but got: %v", err)
+		}
+		if !dryRun && err != nil {
+			t.Errorf("expected sequence config map, but got: %v", err)
+		}
+	}
+
+	// warnings are returned in the response
+	oldSecret := secret.DeepCopy()
+	oldSecret.Data["tenant"] = []byte("7")
+	resp := review(t, handler, admissionv1.Update, secret, oldSecret, false)
+	if !resp.Allowed || len(resp.Warnings) != 1 || !strings.Contains(resp.Warnings[0], "ignored") {
+		t.Errorf("got invalid admission response: %v", resp)
+	}
+
+	// errors are returned with their causes
+	secret.Data["id"] = []byte("%generate:uid")
+	resp = review(t, handler, admissionv1.Create, secret, nil, false)
+	if resp.Allowed || resp.Result == nil || resp.Result.Reason != metav1.StatusReasonInvalid || resp.Result.Details == nil || len(resp.Result.Details.Causes) != 1 {
+		t.Errorf("got invalid admission response: %v", resp)
+	}
+}
+
+func TestValidatingHandler(t *testing.T) {
+	handler := NewValidatingHandler(NewSecretWebhook())
+	secret := &corev1.Secret{
+		Data: map[string][]byte{
+			"id": []byte("%generate:uid"),
+		},
+	}
+	if resp := review(t, handler, admissionv1.Create, secret, nil, false); resp.Allowed {
+		t.Errorf("expected request to be denied, but got: %v", resp)
+	}
+	oldSecret := secret.DeepCopy()
+	if resp := review(t, handler, admissionv1.Update, secret, oldSecret, false); !resp.Allowed {
+		t.Errorf("expected request to be allowed, but got: %v", resp)
+	}
+	if resp := review(t, handler, admissionv1.Delete, nil, oldSecret, false); !resp.Allowed {
+		t.Errorf("expected request to be allowed, but got: %v", resp)
+	}
+}
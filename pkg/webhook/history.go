@@ -0,0 +1,213 @@
+/*
+SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company and secret-generator contributors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/util/retry"
+)
+
+const (
+	// AnnotationKeyHistory enables the history of generated values; its value is the number of previous values kept per generated key,
+	// in a companion secret (named <name>.history) which is owned by the secret.
+	AnnotationKeyHistory = "secret-generator.cs.sap.com/history"
+	// AnnotationKeyRollback restores values from the history whenever its value changes; the value is a version, applying to all generated keys
+	// with this version in the history, or a comma-separated list of <key>=<version> items.
+	AnnotationKeyRollback = "secret-generator.cs.sap.com/rollback"
+	// HistorySecretSuffix is appended to the name of a secret to obtain the name of its companion history secret.
+	HistorySecretSuffix = ".history"
+)
+
+// return the number of previous values to be kept in the history of secret (zero, if the history is disabled)
+func getHistoryLimit(secret *corev1.Secret) (int, error) {
+	v, ok := secret.Annotations[AnnotationKeyHistory]
+	if !ok {
+		return 0, nil
+	}
+	limit, err := strconv.Atoi(v)
+	if err == nil && limit <= 0 {
+		err = fmt.Errorf("must be positive")
+	}
+	if err != nil {
+		return 0, errors.Wrapf(err, "invalid value of annotation %s: %s", AnnotationKeyHistory, v)
+	}
+	return limit, nil
+}
+
+// return the versions of key in the history secret, in ascending order; values are stored as <key>.<version>
+func getHistoryVersions(history *corev1.Secret, key string) []int {
+	var versions []int
+	for k := range history.Data {
+		if i := strings.LastIndex(k, "."); i >= 0 && k[:i] == key {
+			if version, err := strconv.Atoi(k[i+1:]); err == nil {
+				versions = append(versions, version)
+			}
+		}
+	}
+	slices.Sort(versions)
+	return versions
+}
+
+// return the keys and versions to be restored, because the rollback annotation was changed by the update
+func getRollbackVersions(secret *corev1.Secret, oldSecret *corev1.Secret, history *corev1.Secret, keys []string) (map[string]int, error) {
+	value, ok := secret.Annotations[AnnotationKeyRollback]
+	if !ok || value == oldSecret.Annotations[AnnotationKeyRollback] {
+		return nil, nil
+	}
+	versions := make(map[string]int)
+	for _, item := range strings.Split(value, ",") {
+		item = strings.TrimSpace(item)
+		key, v, ok := strings.Cut(item, "=")
+		if !ok {
+			key, v = "", key
+		}
+		version, err := strconv.Atoi(v)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid value of annotation %s: %s", AnnotationKeyRollback, item)
+		}
+		if key == "" {
+			found := false
+			for _, k := range keys {
+				if slices.Contains(getHistoryVersions(history, k), version) {
+					versions[k] = version
+					found = true
+				}
+			}
+			if !found {
+				return nil, fmt.Errorf("error rolling back to version %d: version not found in history", version)
+			}
+			continue
+		}
+		if !slices.Contains(keys, key) {
+			return nil, fmt.Errorf("error rolling back key '%s': not a generated key", key)
+		}
+		if !slices.Contains(getHistoryVersions(history, key), version) {
+			return nil, fmt.Errorf("error rolling back key '%s' to version %d: version not found in history", key, version)
+		}
+		versions[key] = version
+	}
+	return versions, nil
+}
+
+// restore values from the history (if requested by the rollback annotation), and add the previous values of generated keys
+// whose value changed to the history; the history secret is created or updated with optimistic concurrency (but not on dry runs,
+// which only restore values)
+func (w *SecretWebhook) updateHistory(ctx context.Context, secret *corev1.Secret, oldSecret *corev1.Secret, generated map[string]*generatedKey) error {
+	limit, err := getHistoryLimit(secret)
+	if err != nil {
+		return err
+	}
+	_, rollback := secret.Annotations[AnnotationKeyRollback]
+	rollback = rollback && secret.Annotations[AnnotationKeyRollback] != oldSecret.Annotations[AnnotationKeyRollback]
+	if limit == 0 && !rollback {
+		return nil
+	}
+	if w.client == nil {
+		return fmt.Errorf("unable to maintain history of secret %s: no kubernetes client configured", secret.Name)
+	}
+	var keys []string
+	for k, record := range generated {
+		if len(record.Keys) == 0 {
+			keys = append(keys, k)
+		} else {
+			keys = append(keys, record.Keys...)
+		}
+	}
+	slices.Sort(keys)
+
+	dryRun := isDryRun(ctx)
+	secrets := w.client.CoreV1().Secrets(secret.Namespace)
+	name := secret.Name + HistorySecretSuffix
+	data := maps.Clone(secret.Data)
+	retriable := func(err error) bool {
+		return apierrors.IsConflict(err) || apierrors.IsAlreadyExists(err)
+	}
+	err = retry.OnError(retry.DefaultRetry, retriable, func() error {
+		secret.Data = maps.Clone(data)
+		history, err := secrets.Get(ctx, name, metav1.GetOptions{})
+		exists := err == nil
+		if apierrors.IsNotFound(err) {
+			history = &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: secret.Namespace,
+					Name:      name,
+					OwnerReferences: []metav1.OwnerReference{{
+						APIVersion: "v1",
+						Kind:       "Secret",
+						Name:       secret.Name,
+						UID:        secret.UID,
+					}},
+				},
+				Type: corev1.SecretTypeOpaque,
+			}
+		} else if err != nil {
+			return err
+		} else if !slices.ContainsFunc(history.OwnerReferences, func(ref metav1.OwnerReference) bool { return ref.UID == secret.UID }) {
+			return fmt.Errorf("secret %s already exists, but is not owned by secret %s", name, secret.Name)
+		}
+		if history.Data == nil {
+			history.Data = make(map[string][]byte)
+		}
+
+		versions, err := getRollbackVersions(secret, oldSecret, history, keys)
+		if err != nil {
+			return err
+		}
+		for k, version := range versions {
+			secret.Data[k] = history.Data[fmt.Sprintf("%s.%d", k, version)]
+		}
+
+		changed := false
+		if limit > 0 {
+			for _, k := range keys {
+				oldValue, ok := oldSecret.Data[k]
+				if !ok {
+					continue
+				}
+				if value, ok := secret.Data[k]; !ok || bytes.Equal(value, oldValue) {
+					continue
+				}
+				versions := getHistoryVersions(history, k)
+				version := 1
+				if len(versions) > 0 {
+					version = versions[len(versions)-1] + 1
+				}
+				history.Data[fmt.Sprintf("%s.%d", k, version)] = oldValue
+				versions = append(versions, version)
+				for len(versions) > limit {
+					delete(history.Data, fmt.Sprintf("%s.%d", k, versions[0]))
+					versions = versions[1:]
+				}
+				changed = true
+			}
+		}
+		switch {
+		case !changed || dryRun:
+			return nil
+		case exists:
+			_, err = secrets.Update(ctx, history, metav1.UpdateOptions{})
+		default:
+			_, err = secrets.Create(ctx, history, metav1.CreateOptions{})
+		}
+		return err
+	})
+	if err != nil {
+		return errors.Wrapf(err, "error updating history secret %s", name)
+	}
+	return nil
+}
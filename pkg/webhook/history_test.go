@@ -0,0 +1,109 @@
+/*
+SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company and secret-generator contributors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package webhook
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestHistory(t *testing.T) {
+	clientset := fake.NewClientset()
+	w := NewSecretWebhook(WithClient(clientset))
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:   "testing",
+			Name:        "test",
+			UID:         "1234",
+			Annotations: map[string]string{AnnotationKeyHistory: "2"},
+		},
+		Data: map[string][]byte{
+			"password": []byte("%generate"),
+		},
+	}
+	if err := w.MutateCreate(context.TODO(), secret); err != nil {
+		t.Fatalf("got error: %s", err)
+	}
+
+	// rotate three times; the last two previous values are kept
+	var values []string
+	for i := 0; i < 3; i++ {
+		values = append(values, string(secret.Data["password"]))
+		oldSecret := secret.DeepCopy()
+		secret.Annotations[AnnotationKeyRotate] = fmt.Sprintf("2026-10-18T12:00:0%dZ", i)
+		if err := w.MutateUpdate(context.TODO(), oldSecret, secret); err != nil {
+			t.Fatalf("got error: %s", err)
+		}
+	}
+	history, err := clientset.CoreV1().Secrets("testing").Get(context.TODO(), "test.history", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("got error: %s", err)
+	}
+	if len(history.Data) != 2 || string(history.Data["password.2"]) != values[1] || string(history.Data["password.3"]) != values[2] {
+		t.Errorf("got invalid history: %v", history.Data)
+	}
+	if len(history.OwnerReferences) != 1 || history.OwnerReferences[0].UID != "1234" {
+		t.Errorf("got invalid owner references: %v", history.OwnerReferences)
+	}
+
+	// rollback restores the value, and adds the current value to the history
+	current := string(secret.Data["password"])
+	oldSecret := secret.DeepCopy()
+	secret.Annotations[AnnotationKeyRollback] = "password=2"
+	if err := w.MutateUpdate(context.TODO(), oldSecret, secret); err != nil {
+		t.Fatalf("got error: %s", err)
+	}
+	if string(secret.Data["password"]) != values[1] {
+		t.Errorf("value was not rolled back: %s", secret.Data["password"])
+	}
+	history, _ = clientset.CoreV1().Secrets("testing").Get(context.TODO(), "test.history", metav1.GetOptions{})
+	if string(history.Data["password.4"]) != current {
+		t.Errorf("got invalid history: %v", history.Data)
+	}
+
+	// dry runs restore values, but do not write the history
+	oldSecret = secret.DeepCopy()
+	secret.Annotations[AnnotationKeyRollback] = "password=3"
+	dryRun := true
+	ctx := NewContextWithRequest(context.TODO(), &admissionv1.AdmissionRequest{DryRun: &dryRun})
+	if err := w.MutateUpdate(ctx, oldSecret, secret); err != nil {
+		t.Fatalf("got error: %s", err)
+	}
+	if string(secret.Data["password"]) != values[2] {
+		t.Errorf("value was not rolled back: %s", secret.Data["password"])
+	}
+	history, _ = clientset.CoreV1().Secrets("testing").Get(context.TODO(), "test.history", metav1.GetOptions{})
+	if _, ok := history.Data["password.5"]; ok || len(history.Data) != 2 {
+		t.Errorf("history was written on dry run: %v", history.Data)
+	}
+	if err := clientset.CoreV1().Secrets("testing").Delete(context.TODO(), "test.history", metav1.DeleteOptions{}); err != nil {
+		t.Fatalf("got error: %s", err)
+	}
+	oldSecret = secret.DeepCopy()
+	secret.Annotations[AnnotationKeyRotate] = "2026-10-18T13:00:00Z"
+	if err := w.MutateUpdate(ctx, oldSecret, secret); err != nil {
+		t.Fatalf("got error: %s", err)
+	}
+	if _, err := clientset.CoreV1().Secrets("testing").Get(context.TODO(), "test.history", metav1.GetOptions{}); err == nil {
+		t.Error("history secret was created on dry run")
+	}
+	secret = oldSecret
+
+	// unknown version
+	oldSecret = secret.DeepCopy()
+	secret.Annotations[AnnotationKeyRollback] = "1"
+	if err := w.MutateUpdate(context.TODO(), oldSecret, secret); err == nil {
+		t.Error("expected error, but got none")
+	} else {
+		t.Logf("ok; got error: %s", err)
+	}
+}
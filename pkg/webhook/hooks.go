@@ -0,0 +1,58 @@
+/*
+SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company and secret-generator contributors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package webhook
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/sap/secret-generator/pkg/generator"
+)
+
+// GenerateEvent describes the generation of a secret key.
+type GenerateEvent struct {
+	// Secret being mutated; it must not be modified by hooks; note that other keys may still contain placeholders.
+	Secret *corev1.Secret
+	// Key holding the placeholder.
+	Key string
+	// Format of the placeholder (i.e. the part after the prefix).
+	Format string
+	// Values generated (by key); only set for post-generate hooks. Regular generators produce one value for Key,
+	// bundle generators produce multiple values for other keys.
+	Values generator.Values
+	// DryRun indicates that the admission request is a dry run; hooks should not cause any side effects then.
+	DryRun bool
+}
+
+// PreGenerateHook is called before a key is generated; returning an error rejects the admission request.
+// Hooks are not called for keys which are kept, e.g. on updates.
+type PreGenerateHook interface {
+	PreGenerate(ctx context.Context, event *GenerateEvent) error
+}
+
+// PostGenerateHook is called after a key was generated; returning an error rejects the admission request.
+type PostGenerateHook interface {
+	PostGenerate(ctx context.Context, event *GenerateEvent) error
+}
+
+// PreGenerateHookFunc adapts a function to PreGenerateHook.
+type PreGenerateHookFunc func(ctx context.Context, event *GenerateEvent) error
+
+func (f PreGenerateHookFunc) PreGenerate(ctx context.Context, event *GenerateEvent) error {
+	return f(ctx, event)
+}
+
+// PostGenerateHookFunc adapts a function to PostGenerateHook.
+type PostGenerateHookFunc func(ctx context.Context, event *GenerateEvent) error
+
+func (f PostGenerateHookFunc) PostGenerate(ctx context.Context, event *GenerateEvent) error {
+	return f(ctx, event)
+}
+
+// WarningHandler receives warnings about processed secrets, such as ignored placeholders or deprecated arguments;
+// it replaces the default handler, which returns the warnings to the client (as admission warnings).
+type WarningHandler func(ctx context.Context, secret *corev1.Secret, message string)
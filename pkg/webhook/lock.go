@@ -0,0 +1,130 @@
+/*
+SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company and secret-generator contributors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+
+	authenticationv1 "k8s.io/api/authentication/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/sap/secret-generator/pkg/generator"
+)
+
+const (
+	// AnnotationKeyLock (if true) locks the generated keys of a secret, i.e. updates setting them to other values than their recorded
+	// placeholders (or removing them, unless they are retained) are denied, unless the requester is allowed to change locked keys; changing
+	// the annotation itself, or the annotations requesting regeneration, rotation, promotion or rollback, is subject to the same restriction.
+	AnnotationKeyLock = "secret-generator.cs.sap.com/lock"
+)
+
+// UserInfoFunc returns information about the user performing the admission request being processed, or false, if unknown.
+type UserInfoFunc func(ctx context.Context) (authenticationv1.UserInfo, bool)
+
+// check if the generated keys of secret are locked
+func (w *SecretWebhook) isLocked(secret *corev1.Secret) bool {
+	return getBoolAnnotation(secret, AnnotationKeyLock, w.lock)
+}
+
+// return the requester of the admission request being processed; by default, it is taken from the admission request passed in ctx
+func (w *SecretWebhook) getUserInfo(ctx context.Context) (authenticationv1.UserInfo, bool) {
+	if w.userInfo == nil {
+		return userInfoFromRequest(ctx)
+	}
+	return w.userInfo(ctx)
+}
+
+// check if the requester may change locked keys
+func (w *SecretWebhook) isUnlockAllowed(ctx context.Context) bool {
+	user, ok := w.getUserInfo(ctx)
+	if !ok {
+		return false
+	}
+	return slices.Contains(w.unlockUsers, user.Username) || slices.ContainsFunc(user.Groups, func(group string) bool { return slices.Contains(w.unlockGroups, group) })
+}
+
+// return the requester of the admission request passed in ctx (see NewContextWithRequest())
+func userInfoFromRequest(ctx context.Context) (authenticationv1.UserInfo, bool) {
+	req, ok := RequestFromContext(ctx)
+	if !ok {
+		return authenticationv1.UserInfo{}, false
+	}
+	return req.UserInfo, true
+}
+
+// annotations which (if changed) cause locked keys to be set from other sources than the recorded placeholders
+// (such as a changed placeholder, staged values or the history secret), and therefore require the same permission as changing them
+var lockedAnnotations = []string{AnnotationKeyLock, AnnotationKeyOnSpecChange, AnnotationKeyRotate, AnnotationKeyPromote, AnnotationKeyRollback}
+
+// deny updates changing the values of locked generated keys of oldSecret to other values than their recorded placeholder, or removing them
+// (or changing one of the lockedAnnotations), unless the requester is allowed to do so; removing keys is fine if generated keys are retained
+// (since they are restored then), or if the recorded placeholder of their bundle is given (since the bundle is generated anew then);
+// otherwise, their record would be dropped, such that later updates could set them to arbitrary values
+func (w *SecretWebhook) checkLockedKeys(ctx context.Context, secret *corev1.Secret, oldSecret *corev1.Secret) error {
+	if !w.isLocked(oldSecret) {
+		return nil
+	}
+	prefix := w.getPrefix(secret)
+	retained := w.isRetainGenerated(secret)
+	var changed []string
+	generated := getGeneratedKeys(oldSecret)
+	for _, k := range slices.Sorted(maps.Keys(generated)) {
+		record := generated[k]
+		keys := record.Keys
+		format, isPlaceholder := generator.ParseValue(string(secret.Data[k]), prefix)
+		// a changed placeholder would be generated anew (e.g. with on-spec-change regenerate, or on rotation), possibly to a chosen value
+		if isPlaceholder && !w.equalSpecs(record.Spec, format) {
+			changed = append(changed, k)
+			continue
+		}
+		regenerated := isPlaceholder && len(keys) > 0
+		if len(keys) == 0 {
+			keys = []string{k}
+		}
+		for _, key := range keys {
+			oldValue, ok := oldSecret.Data[key]
+			if !ok {
+				continue
+			}
+			value, ok := secret.Data[key]
+			if !ok {
+				if !retained && !regenerated {
+					changed = append(changed, key)
+				}
+				continue
+			}
+			if bytes.Equal(value, oldValue) {
+				continue
+			}
+			if _, ok := generator.ParseValue(string(value), prefix); ok {
+				continue
+			}
+			changed = append(changed, key)
+		}
+	}
+	var changedAnnotations []string
+	for _, annotation := range lockedAnnotations {
+		if secret.Annotations[annotation] != oldSecret.Annotations[annotation] {
+			changedAnnotations = append(changedAnnotations, annotation)
+		}
+	}
+	if (len(changed) == 0 && len(changedAnnotations) == 0) || w.isUnlockAllowed(ctx) {
+		return nil
+	}
+	var err error
+	if len(changed) > 0 {
+		err = fmt.Errorf("generated keys %v are locked, and must not be changed (except to their recorded placeholder) or removed", changed)
+	} else {
+		err = fmt.Errorf("annotations %v must not be changed while generated keys are locked", changedAnnotations)
+	}
+	return apierrors.NewForbidden(schema.GroupResource{Resource: "secrets"}, secret.Name, err)
+}
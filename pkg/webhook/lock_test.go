@@ -0,0 +1,177 @@
+/*
+SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company and secret-generator contributors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package webhook
+
+import (
+	"context"
+	"testing"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+type userKey struct{}
+
+func TestLock(t *testing.T) {
+	w := NewSecretWebhook(
+		WithUnlockAllowed([]string{"admin"}, []string{"operators"}),
+		WithUserInfo(func(ctx context.Context) (authenticationv1.UserInfo, bool) {
+			user, ok := ctx.Value(userKey{}).(authenticationv1.UserInfo)
+			return user, ok
+		}),
+	)
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Annotations: map[string]string{AnnotationKeyLock: "true"},
+		},
+		Data: map[string][]byte{
+			"password": []byte("%generate"),
+			"other":    []byte("value"),
+		},
+	}
+	if err := w.MutateCreate(context.TODO(), secret); err != nil {
+		t.Fatalf("got error: %s", err)
+	}
+	oldSecret := secret.DeepCopy()
+
+	// unmanaged keys may be changed, and the recorded placeholder may be given
+	secret.Data["other"] = []byte("changed")
+	secret.Data["password"] = []byte("%generate")
+	if err := w.MutateUpdate(context.TODO(), oldSecret, secret); err != nil {
+		t.Errorf("got error: %s", err)
+	}
+
+	// locked keys and the lock annotation may not be changed, neither directly, nor by changed placeholders,
+	// rotation or promotion
+	for _, update := range []func(secret *corev1.Secret){
+		func(secret *corev1.Secret) { secret.Data["password"] = []byte("changed") },
+		func(secret *corev1.Secret) { delete(secret.Data, "password") },
+		func(secret *corev1.Secret) { delete(secret.Annotations, AnnotationKeyLock) },
+		func(secret *corev1.Secret) { secret.Data["password"] = []byte("%generate:password:length=16") },
+		func(secret *corev1.Secret) {
+			secret.Annotations[AnnotationKeyOnSpecChange] = onSpecChangeRegenerate
+			secret.Data["password"] = []byte("%generate:choice:values=chosen")
+		},
+		func(secret *corev1.Secret) { secret.Annotations[AnnotationKeyOnSpecChange] = onSpecChangeRegenerate },
+		func(secret *corev1.Secret) { secret.Annotations[AnnotationKeyRotate] = "2026-10-18T12:00:00Z" },
+		func(secret *corev1.Secret) {
+			secret.Data["password.next"] = []byte("chosen")
+			secret.Annotations[AnnotationKeyPromote] = "password"
+		},
+	} {
+		secret = oldSecret.DeepCopy()
+		update(secret)
+		if err := w.MutateUpdate(context.TODO(), oldSecret, secret); !apierrors.IsForbidden(err) {
+			t.Errorf("expected forbidden error, but got: %v", err)
+		} else {
+			t.Logf("ok; got error: %s", err)
+		}
+		ctx := context.WithValue(context.TODO(), userKey{}, authenticationv1.UserInfo{Username: "alice", Groups: []string{"operators"}})
+		if err := w.MutateUpdate(ctx, oldSecret, secret.DeepCopy()); err != nil {
+			t.Errorf("got error: %s", err)
+		}
+		ctx = context.WithValue(context.TODO(), userKey{}, authenticationv1.UserInfo{Username: "bob", Groups: []string{"developers"}})
+		if err := w.MutateUpdate(ctx, oldSecret, secret.DeepCopy()); !apierrors.IsForbidden(err) {
+			t.Errorf("expected forbidden error, but got: %v", err)
+		}
+	}
+
+	// removed keys which are retained keep their record, and remain locked
+	secret = oldSecret.DeepCopy()
+	secret.Annotations[AnnotationKeyRetainGenerated] = "true"
+	delete(secret.Data, "password")
+	if err := w.MutateUpdate(context.TODO(), oldSecret, secret); err != nil {
+		t.Fatalf("got error: %s", err)
+	}
+	if string(secret.Data["password"]) != string(oldSecret.Data["password"]) || getGeneratedKeys(secret)["password"] == nil {
+		t.Fatalf("removed key was not retained: %v", secret.Data)
+	}
+	oldSecret = secret.DeepCopy()
+	secret.Data["password"] = []byte("changed")
+	if err := w.MutateUpdate(context.TODO(), oldSecret, secret); !apierrors.IsForbidden(err) {
+		t.Errorf("expected forbidden error, but got: %v", err)
+	}
+}
+
+func TestLockWithRequest(t *testing.T) {
+	w := NewSecretWebhook(WithLock(true), WithUnlockAllowed([]string{"admin"}, nil))
+	secret := &corev1.Secret{
+		Data: map[string][]byte{
+			"password": []byte("%generate"),
+		},
+	}
+	if err := w.MutateCreate(context.TODO(), secret); err != nil {
+		t.Fatalf("got error: %s", err)
+	}
+	oldSecret := secret.DeepCopy()
+
+	// without user info function, the requester is taken from the admission request
+	secret.Data["password"] = []byte("changed")
+	ctx := NewContextWithRequest(context.TODO(), &admissionv1.AdmissionRequest{UserInfo: authenticationv1.UserInfo{Username: "admin"}})
+	if err := w.MutateUpdate(ctx, oldSecret, secret.DeepCopy()); err != nil {
+		t.Errorf("got error: %s", err)
+	}
+	ctx = NewContextWithRequest(context.TODO(), &admissionv1.AdmissionRequest{UserInfo: authenticationv1.UserInfo{Username: "bob"}})
+	if err := w.MutateUpdate(ctx, oldSecret, secret.DeepCopy()); !apierrors.IsForbidden(err) {
+		t.Errorf("expected forbidden error, but got: %v", err)
+	}
+	if err := w.MutateUpdate(context.TODO(), oldSecret, secret.DeepCopy()); !apierrors.IsForbidden(err) {
+		t.Errorf("expected forbidden error, but got: %v", err)
+	}
+}
+
+func TestLockWithRollback(t *testing.T) {
+	clientset := fake.NewClientset()
+	w := NewSecretWebhook(WithClient(clientset), WithLock(true), WithUnlockAllowed([]string{"admin"}, nil))
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:   "testing",
+			Name:        "test",
+			UID:         "1234",
+			Annotations: map[string]string{AnnotationKeyHistory: "2"},
+		},
+		Data: map[string][]byte{
+			"password": []byte("%generate"),
+		},
+	}
+	if err := w.MutateCreate(context.TODO(), secret); err != nil {
+		t.Fatalf("got error: %s", err)
+	}
+	// the history secret can be edited by anyone allowed to update secrets
+	history := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:       "testing",
+			Name:            "test" + HistorySecretSuffix,
+			OwnerReferences: []metav1.OwnerReference{{APIVersion: "v1", Kind: "Secret", Name: "test", UID: "1234"}},
+		},
+		Data: map[string][]byte{
+			"password.1": []byte("chosen"),
+		},
+	}
+	if _, err := clientset.CoreV1().Secrets("testing").Create(context.TODO(), history, metav1.CreateOptions{}); err != nil {
+		t.Fatalf("got error: %s", err)
+	}
+
+	oldSecret := secret.DeepCopy()
+	secret.Annotations[AnnotationKeyRollback] = "1"
+	ctx := NewContextWithRequest(context.TODO(), &admissionv1.AdmissionRequest{UserInfo: authenticationv1.UserInfo{Username: "bob"}})
+	if err := w.MutateUpdate(ctx, oldSecret, secret.DeepCopy()); !apierrors.IsForbidden(err) {
+		t.Errorf("expected forbidden error, but got: %v", err)
+	} else {
+		t.Logf("ok; got error: %s", err)
+	}
+	ctx = NewContextWithRequest(context.TODO(), &admissionv1.AdmissionRequest{UserInfo: authenticationv1.UserInfo{Username: "admin"}})
+	if err := w.MutateUpdate(ctx, oldSecret, secret); err != nil {
+		t.Fatalf("got error: %s", err)
+	}
+	if string(secret.Data["password"]) != "chosen" {
+		t.Errorf("value was not rolled back: %s", secret.Data["password"])
+	}
+}
@@ -0,0 +1,98 @@
+/*
+SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company and secret-generator contributors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package webhook
+
+import (
+	"bytes"
+	"maps"
+	"slices"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/structured-merge-diff/v6/fieldpath"
+)
+
+// transfer the ownership of the generated keys of secret (as recorded in its managed fields) to the configured field manager,
+// if GitOps mode is enabled; other managers (such as GitOps tools using server-side apply) then no longer own the generated values,
+// so that applying a manifest without the key does not remove it; nothing is done if the secret has no managed fields
+// (e.g. if the webhook's methods are called directly)
+func (w *SecretWebhook) setFieldManager(secret *corev1.Secret, generated map[string]*generatedKey) error {
+	if w.fieldManager == "" || !w.isGitOps(secret) || len(secret.ManagedFields) == 0 {
+		return nil
+	}
+	owned := &fieldpath.Set{}
+	for _, k := range slices.Sorted(maps.Keys(generated)) {
+		keys := generated[k].Keys
+		if len(keys) == 0 {
+			keys = []string{k}
+		}
+		for _, key := range keys {
+			if _, ok := secret.Data[key]; ok {
+				owned.Insert(fieldpath.MakePathOrDie("data", key))
+			}
+		}
+	}
+	// stringData is merged into data before admission, but may still be recorded as owned by the requester
+	stringData := &fieldpath.Set{}
+	owned.Iterate(func(path fieldpath.Path) {
+		stringData.Insert(fieldpath.MakePathOrDie("stringData", *path[1].FieldName))
+	})
+
+	var managedFields []metav1.ManagedFieldsEntry
+	var existing *metav1.ManagedFieldsEntry
+	for _, entry := range secret.ManagedFields {
+		if entry.Manager == w.fieldManager && entry.Operation == metav1.ManagedFieldsOperationUpdate && entry.Subresource == "" {
+			existing = &entry
+			continue
+		}
+		if entry.FieldsType != "FieldsV1" || entry.FieldsV1 == nil {
+			managedFields = append(managedFields, entry)
+			continue
+		}
+		fields := &fieldpath.Set{}
+		if err := fields.FromJSON(bytes.NewReader(entry.FieldsV1.Raw)); err != nil {
+			// invalid entries are reset by the api server anyway
+			managedFields = append(managedFields, entry)
+			continue
+		}
+		remaining := fields.Difference(owned).Difference(stringData)
+		if remaining.Equals(fields) {
+			managedFields = append(managedFields, entry)
+			continue
+		}
+		if remaining.Empty() {
+			continue
+		}
+		raw, err := remaining.ToJSON()
+		if err != nil {
+			return err
+		}
+		entry.FieldsV1 = &metav1.FieldsV1{Raw: raw}
+		managedFields = append(managedFields, entry)
+	}
+	if !owned.Empty() {
+		raw, err := owned.ToJSON()
+		if err != nil {
+			return err
+		}
+		// keep the time of the existing entry, if the owned keys did not change
+		if existing != nil && existing.FieldsV1 != nil && bytes.Equal(existing.FieldsV1.Raw, raw) {
+			secret.ManagedFields = append(managedFields, *existing)
+			return nil
+		}
+		now := metav1.Now()
+		managedFields = append(managedFields, metav1.ManagedFieldsEntry{
+			Manager:    w.fieldManager,
+			Operation:  metav1.ManagedFieldsOperationUpdate,
+			APIVersion: "v1",
+			Time:       &now,
+			FieldsType: "FieldsV1",
+			FieldsV1:   &metav1.FieldsV1{Raw: raw},
+		})
+	}
+	secret.ManagedFields = managedFields
+	return nil
+}
@@ -0,0 +1,95 @@
+/*
+SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company and secret-generator contributors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package webhook
+
+import (
+	"context"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestSetFieldManager(t *testing.T) {
+	w := NewSecretWebhook(WithGitOps(true), WithFieldManager("secret-generator"))
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			ManagedFields: []metav1.ManagedFieldsEntry{
+				{
+					Manager:    "argocd-controller",
+					Operation:  metav1.ManagedFieldsOperationApply,
+					APIVersion: "v1",
+					FieldsType: "FieldsV1",
+					FieldsV1:   &metav1.FieldsV1{Raw: []byte(`{"f:data":{"f:other":{},"f:password":{}},"f:metadata":{"f:labels":{"f:app":{}}}}`)},
+				},
+				{
+					Manager:    "kubectl",
+					Operation:  metav1.ManagedFieldsOperationUpdate,
+					APIVersion: "v1",
+					FieldsType: "FieldsV1",
+					FieldsV1:   &metav1.FieldsV1{Raw: []byte(`{"f:stringData":{"f:ca.crt":{}}}`)},
+				},
+			},
+		},
+		Data: map[string][]byte{
+			"password": []byte("%generate"),
+			"tls":      []byte("%generate:mtls"),
+			"other":    []byte("value"),
+		},
+	}
+	if err := w.MutateCreate(context.TODO(), secret); err != nil {
+		t.Fatalf("got error: %s", err)
+	}
+	// generated keys are owned by the field manager; entries owning nothing else are dropped
+	if n := len(secret.ManagedFields); n != 2 {
+		t.Fatalf("got invalid managed fields: %v", secret.ManagedFields)
+	}
+	if entry := secret.ManagedFields[0]; entry.Manager != "argocd-controller" || string(entry.FieldsV1.Raw) != `{"f:data":{"f:other":{}},"f:metadata":{"f:labels":{"f:app":{}}}}` {
+		t.Errorf("got invalid managed fields entry: %s %s", entry.Manager, entry.FieldsV1.Raw)
+	}
+	entry := secret.ManagedFields[1]
+	if entry.Manager != "secret-generator" || entry.Operation != metav1.ManagedFieldsOperationUpdate || entry.Time == nil {
+		t.Errorf("got invalid managed fields entry: %+v", entry)
+	}
+	if s := string(entry.FieldsV1.Raw); s != `{"f:data":{"f:ca.crt":{},"f:client.crt":{},"f:client.key":{},"f:password":{},"f:server.crt":{},"f:server.key":{}}}` {
+		t.Errorf("got invalid owned fields: %s", s)
+	}
+
+	// an applier taking over a generated key (e.g. by a forced apply of the placeholder) loses it again
+	oldSecret := secret.DeepCopy()
+	secret.ManagedFields[0].FieldsV1 = &metav1.FieldsV1{Raw: []byte(`{"f:data":{"f:other":{},"f:password":{}}}`)}
+	secret.Data["password"] = []byte("%generate")
+	if err := w.MutateUpdate(context.TODO(), oldSecret, secret); err != nil {
+		t.Fatalf("got error: %s", err)
+	}
+	if string(secret.Data["password"]) != string(oldSecret.Data["password"]) {
+		t.Error("generated key was changed")
+	}
+	if s := string(secret.ManagedFields[0].FieldsV1.Raw); s != `{"f:data":{"f:other":{}}}` {
+		t.Errorf("got invalid managed fields entry: %s", s)
+	}
+	if len(secret.ManagedFields) != 2 || !secret.ManagedFields[1].Time.Equal(entry.Time) {
+		t.Errorf("got invalid managed fields: %v", secret.ManagedFields)
+	}
+
+	// managed fields are not changed outside of GitOps mode
+	secret = &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Annotations:   map[string]string{AnnotationKeyGitOps: "false"},
+			ManagedFields: oldSecret.DeepCopy().ManagedFields[:1],
+		},
+		Data: map[string][]byte{
+			"password": []byte("%generate"),
+		},
+	}
+	secret.ManagedFields[0].FieldsV1 = &metav1.FieldsV1{Raw: []byte(`{"f:data":{"f:password":{}}}`)}
+	if err := w.MutateCreate(context.TODO(), secret); err != nil {
+		t.Fatalf("got error: %s", err)
+	}
+	if len(secret.ManagedFields) != 1 || secret.ManagedFields[0].Manager != "argocd-controller" {
+		t.Errorf("got invalid managed fields: %v", secret.ManagedFields)
+	}
+}
@@ -0,0 +1,387 @@
+/*
+SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company and secret-generator contributors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+
+	"github.com/pkg/errors"
+
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/sap/secret-generator/pkg/generator"
+)
+
+const (
+	AnnotationKeyPrefix = "secret-generator.cs.sap.com/prefix"
+	// AnnotationKeyGitOps enables (true) or disables (false) GitOps mode for a secret, overriding the webhook's default;
+	// in GitOps mode, placeholders are recorded, and generated keys are retained by default.
+	AnnotationKeyGitOps = "secret-generator.cs.sap.com/gitops"
+	// AnnotationKeyOnSpecChange sets what happens if the placeholder of an existing key differs from the one used for generation:
+	// warn (default; the existing value is kept, and a warning is returned), keep (the existing value is kept silently),
+	// or regenerate (the key is generated anew from the changed placeholder, like on rotation).
+	AnnotationKeyOnSpecChange = "secret-generator.cs.sap.com/on-spec-change"
+	// AnnotationKeyRetainGenerated (if true) makes the webhook restore generated keys which are removed by an update.
+	AnnotationKeyRetainGenerated = "secret-generator.cs.sap.com/retain-generated"
+	// AnnotationKeySoftFail enables (true) or disables (false) soft-fail mode for a secret, overriding the webhook's default.
+	AnnotationKeySoftFail = "secret-generator.cs.sap.com/soft-fail"
+	DefaultPrefix         = generator.DefaultPrefix
+)
+
+const (
+	onSpecChangeWarn       = "warn"
+	onSpecChangeKeep       = "keep"
+	onSpecChangeRegenerate = "regenerate"
+)
+
+func (w *SecretWebhook) handleCreateSecret(ctx context.Context, secret *corev1.Secret) error {
+	prefix := w.getPrefix(secret)
+	generated := getGeneratedKeys(secret)
+	placeholders := getPlaceholders(secret)
+	// keys recorded as literals (e.g. because the secret is a copy of another one) are taken as they are
+	literals := getLiteralKeys(secret)
+	failures := w.newFailures(ctx, secret)
+	for _, k := range slices.Sorted(maps.Keys(secret.Data)) {
+		if literals[k] {
+			continue
+		}
+		// escaped literals (such as %%generate) are unescaped, but not interpreted as placeholder
+		if value, ok := generator.Unescape(string(secret.Data[k]), prefix); ok {
+			secret.Data[k] = []byte(value)
+			literals[k] = true
+			continue
+		}
+		if format, ok := generator.ParseValue(string(secret.Data[k]), prefix); ok {
+			placeholders[k] = string(secret.Data[k])
+			if w.registry.IsBundle(format) {
+				generatedValues, err := w.generateBundle(ctx, secret, k, format)
+				if err != nil {
+					if err := failures.add(k, errors.Wrapf(err, "error generating values for key '%s'", k)); err != nil {
+						return err
+					}
+					continue
+				}
+				if bk, ok := findExistingKey(secret, generatedValues); ok {
+					if err := failures.add(k, fmt.Errorf("error generating values for key '%s': key '%s' already exists", k, bk)); err != nil {
+						return err
+					}
+					continue
+				}
+				delete(secret.Data, k)
+				for bk, bv := range generatedValues {
+					secret.Data[bk] = []byte(bv)
+				}
+				generated[k] = newGeneratedKey(format, slices.Sorted(maps.Keys(generatedValues)))
+				continue
+			}
+			generatedValue, err := w.generateValue(ctx, secret, k, format)
+			if err != nil {
+				if err := failures.add(k, errors.Wrapf(err, "error generating value for key '%s'", k)); err != nil {
+					return err
+				}
+				continue
+			}
+			secret.Data[k] = []byte(generatedValue)
+			generated[k] = newGeneratedKey(format, nil)
+		}
+	}
+	setGeneratedKeys(secret, generated)
+	setLiteralKeys(secret, literals)
+	w.setPlaceholders(secret, placeholders, generated)
+	if err := w.setFieldManager(secret, generated); err != nil {
+		return errors.Wrap(err, "error updating managed fields")
+	}
+	failures.record(secret)
+	return nil
+}
+
+func (w *SecretWebhook) handleUpdateSecret(ctx context.Context, secret *corev1.Secret, oldSecret *corev1.Secret) error {
+	prefix := w.getPrefix(secret)
+	generated := getGeneratedKeys(oldSecret)
+	placeholders := getPlaceholders(oldSecret)
+	oldLiterals := getLiteralKeys(oldSecret)
+	literals := make(map[string]bool)
+	failures := w.newFailures(ctx, secret)
+	oldErrors := getGenerationErrors(oldSecret)
+	if w.isRetainGenerated(secret) {
+		w.retainGeneratedKeys(ctx, secret, oldSecret, generated)
+	}
+	rotated, unknown := w.getRotatedKeys(secret, oldSecret, generated)
+	for _, k := range unknown {
+		w.warn(ctx, secret, fmt.Sprintf("key '%s' is not a generated key, and therefore not rotated", k))
+	}
+	for _, k := range slices.Sorted(maps.Keys(secret.Data)) {
+		// unchanged values are taken as they are; in particular, literals looking like placeholders (e.g. unescaped by an earlier request)
+		// are neither unescaped again, nor adopted as generated keys; only keys which still hold their placeholder because of an error are retried
+		if isKeptKey(oldSecret, oldErrors, k) && bytes.Equal(secret.Data[k], oldSecret.Data[k]) {
+			literals[k] = oldLiterals[k]
+			continue
+		}
+		// escaped literals (such as %%generate) are unescaped, but not interpreted as placeholder
+		if value, ok := generator.Unescape(string(secret.Data[k]), prefix); ok {
+			secret.Data[k] = []byte(value)
+			literals[k] = true
+			continue
+		}
+		if format, ok := generator.ParseValue(string(secret.Data[k]), prefix); ok {
+			placeholders[k] = string(secret.Data[k])
+			// rotated keys holding a placeholder are generated from that placeholder; so are keys whose placeholder changed,
+			// if requested by the on-spec-change policy
+			rotate := rotated[k] || w.isRegeneratedOnSpecChange(secret, k, format, generated)
+			delete(rotated, k)
+			if w.registry.IsBundle(format) {
+				// a bundle is only kept if all of its keys exist; otherwise it is generated anew as a whole
+				keys, err := w.registry.BundleKeys(format)
+				if err != nil {
+					if err := failures.add(k, errors.Wrapf(err, "error generating values for key '%s'", k)); err != nil {
+						return err
+					}
+					continue
+				}
+				complete := true
+				for _, bk := range keys {
+					if _, ok := oldSecret.Data[bk]; !ok {
+						complete = false
+					}
+				}
+				if complete && !rotate {
+					delete(secret.Data, k)
+					for _, bk := range keys {
+						secret.Data[bk] = oldSecret.Data[bk]
+					}
+					w.checkKeptSpec(ctx, secret, k, format, generated)
+					if generated[k] != nil {
+						generated[k].Keys = keys
+					}
+				} else {
+					generatedValues, err := w.generateBundle(ctx, secret, k, format)
+					if err != nil {
+						if err := failures.add(k, errors.Wrapf(err, "error generating values for key '%s'", k)); err != nil {
+							return err
+						}
+						continue
+					}
+					delete(secret.Data, k)
+					if rotate {
+						setRotatedValues(secret, oldSecret, generatedValues)
+					} else {
+						for bk, bv := range generatedValues {
+							secret.Data[bk] = []byte(bv)
+						}
+					}
+					generated[k] = newGeneratedKey(format, slices.Sorted(maps.Keys(generatedValues)))
+				}
+				continue
+			}
+			if isKeptKey(oldSecret, oldErrors, k) && !rotate {
+				secret.Data[k] = oldSecret.Data[k]
+				literals[k] = oldLiterals[k]
+				w.checkKeptSpec(ctx, secret, k, format, generated)
+			} else {
+				generatedValue, err := w.generateValue(ctx, secret, k, format)
+				if err != nil {
+					if err := failures.add(k, errors.Wrapf(err, "error generating value for key '%s'", k)); err != nil {
+						return err
+					}
+					continue
+				}
+				if rotate {
+					setRotatedValues(secret, oldSecret, generator.Values{k: generatedValue})
+				} else {
+					secret.Data[k] = []byte(generatedValue)
+				}
+				generated[k] = newGeneratedKey(format, nil)
+			}
+		}
+	}
+	// other rotated keys are generated from their recorded placeholders
+	for _, k := range slices.Sorted(maps.Keys(rotated)) {
+		if err := w.rotateKey(ctx, secret, oldSecret, k, generated[k]); err != nil {
+			if err := failures.add(k, err); err != nil {
+				return err
+			}
+		}
+	}
+	w.promoteKeys(ctx, secret, oldSecret, generated)
+	if err := w.updateHistory(ctx, secret, oldSecret, generated); err != nil {
+		return err
+	}
+	setGeneratedKeys(secret, generated)
+	setLiteralKeys(secret, literals)
+	w.setPlaceholders(secret, placeholders, generated)
+	if err := w.setFieldManager(secret, generated); err != nil {
+		return errors.Wrap(err, "error updating managed fields")
+	}
+	failures.record(secret)
+	return nil
+}
+
+// check if generated keys are retained for secret; this is the default in GitOps mode
+func (w *SecretWebhook) isRetainGenerated(secret *corev1.Secret) bool {
+	return getBoolAnnotation(secret, AnnotationKeyRetainGenerated, w.isGitOps(secret))
+}
+
+// restore the generated keys of oldSecret which were removed by the update; bundles whose placeholder is contained
+// in the update are not restored, since they are handled as usual
+func (w *SecretWebhook) retainGeneratedKeys(ctx context.Context, secret *corev1.Secret, oldSecret *corev1.Secret, generated map[string]*generatedKey) {
+	for _, k := range slices.Sorted(maps.Keys(generated)) {
+		if _, ok := secret.Data[k]; ok {
+			continue
+		}
+		keys := generated[k].Keys
+		if len(keys) == 0 {
+			keys = []string{k}
+		}
+		for _, key := range keys {
+			if _, ok := secret.Data[key]; ok {
+				continue
+			}
+			if v, ok := oldSecret.Data[key]; ok {
+				if secret.Data == nil {
+					secret.Data = make(map[string][]byte)
+				}
+				secret.Data[key] = v
+				w.warn(ctx, secret, fmt.Sprintf("generated key '%s' was removed by the update, and is restored", key))
+			}
+		}
+	}
+}
+
+// check if the placeholder of an existing key differs from the recorded one, and the on-spec-change policy of secret
+// requests the key to be generated anew in that case
+func (w *SecretWebhook) isRegeneratedOnSpecChange(secret *corev1.Secret, key string, format string, generated map[string]*generatedKey) bool {
+	if secret.Annotations[AnnotationKeyOnSpecChange] != onSpecChangeRegenerate {
+		return false
+	}
+	record, ok := generated[key]
+	return ok && !w.equalSpecs(record.Spec, format)
+}
+
+// check if key of oldSecret is kept on update; keys which still hold their placeholder, because their generation
+// failed in soft-fail mode (as recorded in oldErrors), are not kept, but generated again
+func isKeptKey(oldSecret *corev1.Secret, oldErrors map[string]string, key string) bool {
+	if _, ok := oldSecret.Data[key]; !ok {
+		return false
+	}
+	_, failed := oldErrors[key]
+	return !failed
+}
+
+// return one of the given keys which already exists in secret
+func findExistingKey(secret *corev1.Secret, values generator.Values) (string, bool) {
+	for _, k := range slices.Sorted(maps.Keys(values)) {
+		if _, ok := secret.Data[k]; ok {
+			return k, true
+		}
+	}
+	return "", false
+}
+
+// warn that the placeholder of a kept key is ignored, unless it is equivalent to the recorded one (or the on-spec-change policy is keep);
+// if there is no record (e.g. because the key was generated by an older version of the webhook), the placeholder is recorded,
+// unless the kept value is a literal looking like a placeholder (which was obviously not generated)
+func (w *SecretWebhook) checkKeptSpec(ctx context.Context, secret *corev1.Secret, key string, format string, generated map[string]*generatedKey) {
+	record, ok := generated[key]
+	switch {
+	case !ok:
+		w.warn(ctx, secret, fmt.Sprintf("placeholder of key '%s' is ignored, since the key already exists (existing values are never regenerated)", key))
+		if _, literal := generator.ParseValue(string(secret.Data[key]), w.getPrefix(secret)); !literal {
+			generated[key] = adoptGeneratedKey(format)
+		}
+	case secret.Annotations[AnnotationKeyOnSpecChange] == onSpecChangeKeep:
+	case !w.equalSpecs(record.Spec, format):
+		w.warn(ctx, secret, fmt.Sprintf("placeholder of key '%s' differs from the one used for generation (%s); the change is ignored, unless the key is rotated", key, record.Spec))
+	}
+}
+
+func (w *SecretWebhook) warn(ctx context.Context, secret *corev1.Secret, message string) {
+	w.warningHandler(ctx, secret, message)
+}
+
+// check if soft-fail mode is enabled for secret
+func (w *SecretWebhook) isSoftFail(secret *corev1.Secret) bool {
+	return getBoolAnnotation(secret, AnnotationKeySoftFail, w.softFail)
+}
+
+// check if GitOps mode is enabled for secret
+func (w *SecretWebhook) isGitOps(secret *corev1.Secret) bool {
+	return getBoolAnnotation(secret, AnnotationKeyGitOps, w.gitOps)
+}
+
+// return the value of a boolean annotation of secret, or the given default, if the annotation is not set (or invalid)
+func getBoolAnnotation(secret *corev1.Secret, key string, defaultValue bool) bool {
+	if v, ok := secret.Annotations[key]; ok {
+		if b, err := strconv.ParseBool(v); err == nil {
+			return b
+		}
+	}
+	return defaultValue
+}
+
+// return the placeholder prefix used in secret
+func (w *SecretWebhook) getPrefix(secret *corev1.Secret) string {
+	if v, ok := secret.Annotations[AnnotationKeyPrefix]; ok {
+		return v
+	}
+	return w.prefix
+}
+
+func (w *SecretWebhook) generateBundle(ctx context.Context, secret *corev1.Secret, key string, format string) (generator.Values, error) {
+	return w.generate(ctx, secret, key, format, w.registry.GenerateBundle)
+}
+
+func (w *SecretWebhook) generateValue(ctx context.Context, secret *corev1.Secret, key string, format string) (string, error) {
+	values, err := w.generate(ctx, secret, key, format, func(ctx context.Context, format string) (generator.Values, error) {
+		value, err := w.registry.GenerateValue(ctx, format)
+		if err != nil {
+			return nil, err
+		}
+		return generator.Values{key: value}, nil
+	})
+	if err != nil {
+		return "", err
+	}
+	return values[key], nil
+}
+
+// run generate, surrounded by the pre- and post-generate hooks
+func (w *SecretWebhook) generate(ctx context.Context, secret *corev1.Secret, key string, format string, generate func(ctx context.Context, format string) (generator.Values, error)) (generator.Values, error) {
+	if g, args, err := w.registry.Parse(format); err == nil {
+		for _, arg := range g.Schema().Arguments {
+			if arg.Deprecated != "" && args.Has(arg.Name) {
+				w.warn(ctx, secret, fmt.Sprintf("argument %s of %s generator (key '%s') is deprecated: %s", arg.Name, g.Name(), key, arg.Deprecated))
+			}
+		}
+	}
+	dryRun := isDryRun(ctx)
+	event := &GenerateEvent{Secret: secret, Key: key, Format: format, DryRun: dryRun}
+	for _, hook := range w.preGenerateHooks {
+		if err := hook.PreGenerate(ctx, event); err != nil {
+			return nil, err
+		}
+	}
+	env := &generator.Environment{Client: w.client, Namespace: secret.Namespace, SequenceNamespace: w.sequenceNamespace, DryRun: dryRun, Random: w.random}
+	if user, ok := w.getUserInfo(ctx); ok {
+		env.User = &user
+	}
+	values, err := generate(generator.WithEnvironment(ctx, env), format)
+	if err != nil {
+		return nil, err
+	}
+	w.log.V(1).Info("generated secret key", "namespace", secret.Namespace, "name", secret.Name, "key", key, "keys", slices.Sorted(maps.Keys(values)))
+	event.Values = values
+	for _, hook := range w.postGenerateHooks {
+		if err := hook.PostGenerate(ctx, event); err != nil {
+			return nil, err
+		}
+	}
+	return values, nil
+}
@@ -0,0 +1,617 @@
+/*
+SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company and secret-generator contributors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package webhook
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+
+	"github.com/sap/secret-generator/pkg/generator"
+)
+
+var mtlsKeys = []string{generator.MTLSKeyCACert, generator.MTLSKeyServerCert, generator.MTLSKeyServerKey, generator.MTLSKeyClientCert, generator.MTLSKeyClientKey}
+
+func TestHandleCreateSecret(t *testing.T) {
+	w := NewSecretWebhook()
+	secret := &corev1.Secret{
+		Data: map[string][]byte{
+			"key1": []byte("%generate"),
+			"key2": []byte("%generate:password:length=8"),
+			"key3": []byte("%generate:uuid"),
+			"key4": []byte("value"),
+			"key5": []byte("%%generate:uuid"),
+		},
+	}
+	if err := w.handleCreateSecret(context.TODO(), secret); err != nil {
+		t.Fatalf("handleCreateSecret: got errror: %s", err)
+	}
+	if s := string(secret.Data["key1"]); len(s) != 32 {
+		t.Errorf("handleCreateSecret: got invalid password: %s", s)
+	}
+	if s := string(secret.Data["key2"]); len(s) != 8 {
+		t.Errorf("handleCreateSecret: got invalid password: %s", s)
+	}
+	if _, err := uuid.Parse(string(secret.Data["key3"])); err != nil {
+		t.Errorf("handleCreateSecret: got invalid uuid; error: %s", err)
+	}
+	if s := string(secret.Data["key4"]); s != "value" {
+		t.Errorf("handleCreateSecret: got invalid unmanaged value: %s", s)
+	}
+	if s := string(secret.Data["key5"]); s != "%generate:uuid" {
+		t.Errorf("handleCreateSecret: got invalid escaped value: %s", s)
+	}
+}
+
+func TestHandleCreateSecretWithError(t *testing.T) {
+	w := NewSecretWebhook()
+	secret := &corev1.Secret{
+		Data: map[string][]byte{
+			"key1": []byte("%generate:foobar"),
+		},
+	}
+	if err := w.handleCreateSecret(context.TODO(), secret); err == nil {
+		t.Error("handleCreateSecret: expected error, but got none")
+	} else {
+		t.Logf("ok; got error: %s", err)
+	}
+}
+
+func TestHandleUpdateSecret(t *testing.T) {
+	w := NewSecretWebhook()
+	secret := &corev1.Secret{
+		Data: map[string][]byte{
+			"key1":         []byte("%generate"),
+			"key2":         []byte("%generate:password:length=8"),
+			"key3":         []byte("%generate:uuid"),
+			"key4":         []byte("value"),
+			"existingKey1": []byte("%generate"),
+			"existingKey2": []byte("%generate:password:length=8"),
+			"existingKey3": []byte("%generate:uuid"),
+			"existingKey4": []byte("value"),
+		},
+	}
+	oldSecret := &corev1.Secret{
+		Data: map[string][]byte{
+			"existingKey1": []byte("ABCDEFGHIJKLMabcdefghijklm012345"),
+			"existingKey2": []byte("ABCabc12"),
+			"existingKey3": []byte("eb89b65f-cd54-40b4-8122-e8d42ce3c324"),
+			"existingKey4": []byte("value"),
+		},
+	}
+	if err := w.handleUpdateSecret(context.TODO(), secret, oldSecret); err != nil {
+		t.Fatalf("handleUpdateSecret: got errror: %s", err)
+	}
+	if s := string(secret.Data["key1"]); len(s) != 32 {
+		t.Errorf("handleUpdateSecret: got invalid password: %s", s)
+	}
+	if s := string(secret.Data["key2"]); len(s) != 8 {
+		t.Errorf("handleUpdateSecret: got invalid password: %s", s)
+	}
+	if _, err := uuid.Parse(string(secret.Data["key3"])); err != nil {
+		t.Errorf("handleUpdateSecret: got invalid uuid; error: %s", err)
+	}
+	if s := string(secret.Data["key4"]); s != "value" {
+		t.Errorf("handleUpdateSecret: got invalid unmanaged value: %s", s)
+	}
+	if string(secret.Data["existingKey1"]) != string(oldSecret.Data["existingKey1"]) {
+		t.Error("handleUpdateSecret: existing value got changed")
+	}
+	if string(secret.Data["existingKey2"]) != string(oldSecret.Data["existingKey2"]) {
+		t.Error("handleUpdateSecret: existing value got changed")
+	}
+	if string(secret.Data["existingKey3"]) != string(oldSecret.Data["existingKey3"]) {
+		t.Error("handleUpdateSecret: existing value got changed")
+	}
+	if string(secret.Data["existingKey4"]) != string(oldSecret.Data["existingKey4"]) {
+		t.Error("handleUpdateSecret: existing value got changed")
+	}
+}
+
+func TestHandleUpdateSecretWithError(t *testing.T) {
+	w := NewSecretWebhook()
+	secret := &corev1.Secret{
+		Data: map[string][]byte{
+			"key1": []byte("%generate:foobar"),
+		},
+	}
+	oldSecret := &corev1.Secret{}
+	if err := w.handleUpdateSecret(context.TODO(), secret, oldSecret); err == nil {
+		t.Error("handleUpdateSecret: expected error, but got none")
+	} else {
+		t.Logf("ok; got error: %s", err)
+	}
+}
+
+func TestHandleCreateSecretWithBundle(t *testing.T) {
+	w := NewSecretWebhook()
+	secret := &corev1.Secret{
+		Data: map[string][]byte{
+			"tls":  []byte("%generate:mtls:dns_names=db,db.testing.svc"),
+			"key1": []byte("value"),
+		},
+	}
+	if err := w.handleCreateSecret(context.TODO(), secret); err != nil {
+		t.Fatalf("handleCreateSecret: got errror: %s", err)
+	}
+	if _, ok := secret.Data["tls"]; ok {
+		t.Error("handleCreateSecret: bundle placeholder was not removed")
+	}
+	for _, k := range mtlsKeys {
+		if len(secret.Data[k]) == 0 {
+			t.Errorf("handleCreateSecret: missing bundle key: %s", k)
+		}
+	}
+	if s := string(secret.Data["key1"]); s != "value" {
+		t.Errorf("handleCreateSecret: got invalid unmanaged value: %s", s)
+	}
+
+	secret = &corev1.Secret{
+		Data: map[string][]byte{
+			"tls":    []byte("%generate:mtls"),
+			"ca.crt": []byte("value"),
+		},
+	}
+	if err := w.handleCreateSecret(context.TODO(), secret); err == nil {
+		t.Error("handleCreateSecret: expected error, but got none")
+	} else {
+		t.Logf("ok; got error: %s", err)
+	}
+}
+
+func TestHandleCreateSecretWithMultipleBundles(t *testing.T) {
+	w := NewSecretWebhook()
+	secret := &corev1.Secret{
+		Data: map[string][]byte{
+			"db":    []byte("%generate:mtls:key_prefix=db-"),
+			"cache": []byte("%generate:mtls:key_prefix=cache-"),
+		},
+	}
+	if err := w.handleCreateSecret(context.TODO(), secret); err != nil {
+		t.Fatalf("handleCreateSecret: got errror: %s", err)
+	}
+	if len(secret.Data) != 2*len(mtlsKeys) {
+		t.Errorf("handleCreateSecret: got invalid number of keys: %d", len(secret.Data))
+	}
+	for _, k := range mtlsKeys {
+		if len(secret.Data["db-"+k]) == 0 || len(secret.Data["cache-"+k]) == 0 {
+			t.Errorf("handleCreateSecret: missing bundle key: %s", k)
+		}
+		if string(secret.Data["db-"+k]) == string(secret.Data["cache-"+k]) {
+			t.Errorf("handleCreateSecret: bundles share value of key: %s", k)
+		}
+	}
+
+	// bundles producing the same keys are rejected
+	secret = &corev1.Secret{
+		Data: map[string][]byte{
+			"tls1": []byte("%generate:mtls"),
+			"tls2": []byte("%generate:mtls:dns_names=db"),
+		},
+	}
+	if err := w.handleCreateSecret(context.TODO(), secret); err == nil {
+		t.Error("handleCreateSecret: expected error, but got none")
+	} else {
+		t.Logf("ok; got error: %s", err)
+	}
+}
+
+func TestHandleUpdateSecretWithBundle(t *testing.T) {
+	w := NewSecretWebhook()
+	oldSecret := &corev1.Secret{
+		Data: map[string][]byte{
+			"ca.crt":     []byte("ca"),
+			"server.crt": []byte("server-cert"),
+			"server.key": []byte("server-key"),
+			"client.crt": []byte("client-cert"),
+			"client.key": []byte("client-key"),
+		},
+	}
+	secret := &corev1.Secret{
+		Data: map[string][]byte{
+			"tls": []byte("%generate:mtls"),
+		},
+	}
+	if err := w.handleUpdateSecret(context.TODO(), secret, oldSecret); err != nil {
+		t.Fatalf("handleUpdateSecret: got errror: %s", err)
+	}
+	if _, ok := secret.Data["tls"]; ok {
+		t.Error("handleUpdateSecret: bundle placeholder was not removed")
+	}
+	for _, k := range mtlsKeys {
+		if string(secret.Data[k]) != string(oldSecret.Data[k]) {
+			t.Error("handleUpdateSecret: existing value got changed")
+		}
+	}
+
+	// incomplete bundles are generated anew
+	delete(oldSecret.Data, "client.key")
+	secret = &corev1.Secret{
+		Data: map[string][]byte{
+			"tls": []byte("%generate:mtls"),
+		},
+	}
+	if err := w.handleUpdateSecret(context.TODO(), secret, oldSecret); err != nil {
+		t.Fatalf("handleUpdateSecret: got errror: %s", err)
+	}
+	for _, k := range mtlsKeys {
+		if string(secret.Data[k]) == string(oldSecret.Data[k]) || len(secret.Data[k]) == 0 {
+			t.Errorf("handleUpdateSecret: bundle key was not regenerated: %s", k)
+		}
+	}
+}
+
+func TestHandleCreateSecretWithSSHCertificate(t *testing.T) {
+	_, caKey, _ := ed25519.GenerateKey(rand.Reader)
+	der, _ := x509.MarshalPKCS8PrivateKey(caKey)
+	caSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "testing",
+			Name:      "ssh-ca",
+		},
+		Data: map[string][]byte{
+			"ca.key": pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}),
+		},
+	}
+	client := fake.NewClientset(caSecret)
+	// only alice may read the ca secret
+	client.PrependReactor("create", "subjectaccessreviews", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		review := action.(k8stesting.CreateAction).GetObject().(*authorizationv1.SubjectAccessReview)
+		attributes := review.Spec.ResourceAttributes
+		review.Status.Allowed = review.Spec.User == "alice" && attributes.Verb == "get" && attributes.Resource == "secrets" && attributes.Namespace == "testing" && attributes.Name == "ssh-ca"
+		return true, review, nil
+	})
+	w := NewSecretWebhook(WithClient(client))
+	ctx := NewContextWithRequest(context.TODO(), &admissionv1.AdmissionRequest{UserInfo: authenticationv1.UserInfo{Username: "alice"}})
+
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "testing",
+		},
+		Data: map[string][]byte{
+			"key1": []byte("%generate:ssh:ca_secret=ssh-ca;ca_key=ca.key;principals=alice;validity=1d"),
+		},
+	}
+	if err := w.handleCreateSecret(ctx, secret.DeepCopy()); err != nil {
+		t.Fatalf("handleCreateSecret: got errror: %s", err)
+	}
+
+	// requesters not allowed to read the ca secret, or unknown requesters, do not obtain certificates
+	for _, ctx := range []context.Context{
+		NewContextWithRequest(context.TODO(), &admissionv1.AdmissionRequest{UserInfo: authenticationv1.UserInfo{Username: "bob"}}),
+		context.TODO(),
+	} {
+		if err := w.handleCreateSecret(ctx, secret.DeepCopy()); err == nil {
+			t.Error("handleCreateSecret: expected error, but got none")
+		} else {
+			t.Logf("ok; got error: %s", err)
+		}
+	}
+
+	if err := w.handleCreateSecret(ctx, secret); err != nil {
+		t.Fatalf("handleCreateSecret: got errror: %s", err)
+	}
+	for _, k := range []string{generator.SSHKeyPrivateKey, generator.SSHKeyPublicKey, generator.SSHKeyCertificate} {
+		if len(secret.Data[k]) == 0 {
+			t.Errorf("handleCreateSecret: missing bundle key: %s", k)
+		}
+	}
+	if s := string(secret.Data[generator.SSHKeyCertificate]); !strings.HasPrefix(s, "ssh-ed25519-cert-v01@openssh.com ") {
+		t.Errorf("handleCreateSecret: got invalid certificate: %s", s)
+	}
+
+	// missing ca secret
+	secret = &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "testing",
+		},
+		Data: map[string][]byte{
+			"key1": []byte("%generate:ssh:ca_secret=other-ca"),
+		},
+	}
+	if err := w.handleCreateSecret(ctx, secret); err == nil {
+		t.Error("handleCreateSecret: expected error, but got none")
+	} else {
+		t.Logf("ok; got error: %s", err)
+	}
+
+	// no client
+	w = NewSecretWebhook()
+	secret = &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "testing",
+		},
+		Data: map[string][]byte{
+			"key1": []byte("%generate:ssh:ca_secret=ssh-ca"),
+		},
+	}
+	if err := w.handleCreateSecret(context.TODO(), secret); err == nil {
+		t.Error("handleCreateSecret: expected error, but got none")
+	} else {
+		t.Logf("ok; got error: %s", err)
+	}
+}
+
+func TestHandleSecretWithSoftFail(t *testing.T) {
+	w := NewSecretWebhook(WithSoftFail(true))
+	secret := &corev1.Secret{
+		Data: map[string][]byte{
+			"key1":   []byte("%generate:foobar"),
+			"key2":   []byte("%generate:uuid"),
+			"key3":   []byte("%generate:mtls"),
+			"ca.crt": []byte("value"),
+		},
+	}
+	if err := w.MutateCreate(context.TODO(), secret); err != nil {
+		t.Fatalf("MutateCreate: got error: %s", err)
+	}
+	if s := string(secret.Data["key1"]); s != "%generate:foobar" {
+		t.Errorf("MutateCreate: placeholder of failed key was not kept: %s", s)
+	}
+	if s := string(secret.Data["key3"]); s != "%generate:mtls" {
+		t.Errorf("MutateCreate: placeholder of failed bundle was not kept: %s", s)
+	}
+	if _, err := uuid.Parse(string(secret.Data["key2"])); err != nil {
+		t.Errorf("MutateCreate: got invalid uuid; error: %s", err)
+	}
+	errs := getGenerationErrors(secret)
+	if len(errs) != 2 || errs["key1"] == "" || errs["key3"] == "" {
+		t.Errorf("MutateCreate: got invalid %s annotation: %s", AnnotationKeyErrors, secret.Annotations[AnnotationKeyErrors])
+	}
+	if err := w.ValidateCreate(context.TODO(), secret); err != nil {
+		t.Errorf("ValidateCreate: got error: %s", err)
+	}
+
+	// failed keys are generated again on update, and their errors are cleared
+	oldSecret := secret.DeepCopy()
+	secret.Data["key1"] = []byte("%generate:uuid")
+	delete(secret.Data, "ca.crt")
+	if err := w.MutateUpdate(context.TODO(), oldSecret, secret); err != nil {
+		t.Fatalf("MutateUpdate: got error: %s", err)
+	}
+	if _, err := uuid.Parse(string(secret.Data["key1"])); err != nil {
+		t.Errorf("MutateUpdate: got invalid uuid; error: %s", err)
+	}
+	if string(secret.Data["key2"]) != string(oldSecret.Data["key2"]) {
+		t.Error("MutateUpdate: existing value got changed")
+	}
+	for _, k := range mtlsKeys {
+		if len(secret.Data[k]) == 0 {
+			t.Errorf("MutateUpdate: missing bundle key: %s", k)
+		}
+	}
+	if _, ok := secret.Annotations[AnnotationKeyErrors]; ok {
+		t.Errorf("MutateUpdate: %s annotation was not removed", AnnotationKeyErrors)
+	}
+
+	// soft-fail mode can be disabled per secret
+	secret = &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Annotations: map[string]string{AnnotationKeySoftFail: "false"},
+		},
+		Data: map[string][]byte{
+			"key1": []byte("%generate:foobar"),
+		},
+	}
+	if err := w.MutateCreate(context.TODO(), secret); err == nil {
+		t.Error("MutateCreate: expected error, but got none")
+	} else {
+		t.Logf("ok; got error: %s", err)
+	}
+	if err := w.ValidateCreate(context.TODO(), secret); err == nil {
+		t.Error("ValidateCreate: expected error, but got none")
+	} else {
+		t.Logf("ok; got error: %s", err)
+	}
+}
+
+func TestHandleCreateSecretRecordsProvenance(t *testing.T) {
+	w := NewSecretWebhook()
+	secret := &corev1.Secret{
+		Data: map[string][]byte{
+			"key1": []byte("%generate:password:num_symbols=0;length=16"),
+			"key2": []byte("%generate:mtls"),
+			"key3": []byte("value"),
+		},
+	}
+	if err := w.handleCreateSecret(context.TODO(), secret); err != nil {
+		t.Fatalf("handleCreateSecret: got errror: %s", err)
+	}
+	generated := getGeneratedKeys(secret)
+	if len(generated) != 2 {
+		t.Fatalf("handleCreateSecret: got invalid records: %s", secret.Annotations[AnnotationKeyGenerated])
+	}
+	record := generated["key1"]
+	if record.Spec != "password:num_symbols=0;length=16" || record.Hash != hashSpec(record.Spec) || record.Version != generator.Version() || record.GeneratedAt == nil {
+		t.Errorf("handleCreateSecret: got invalid record: %+v", record)
+	}
+	if record := generated["key2"]; record.Spec != "mtls" || len(record.Keys) != len(mtlsKeys) {
+		t.Errorf("handleCreateSecret: got invalid record: %+v", record)
+	}
+}
+
+func TestHandleUpdateSecretWithOnSpecChange(t *testing.T) {
+	var warnings []string
+	w := NewSecretWebhook(WithWarningHandler(func(ctx context.Context, secret *corev1.Secret, message string) {
+		warnings = append(warnings, message)
+	}))
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Annotations: map[string]string{AnnotationKeyOnSpecChange: "keep"},
+		},
+		Data: map[string][]byte{
+			"key1": []byte("%generate:password:length=16"),
+		},
+	}
+	if err := w.MutateCreate(context.TODO(), secret); err != nil {
+		t.Fatalf("MutateCreate: got error: %s", err)
+	}
+
+	// keep: the change is ignored without warning
+	oldSecret := secret.DeepCopy()
+	secret.Data["key1"] = []byte("%generate:password:length=32")
+	if err := w.MutateUpdate(context.TODO(), oldSecret, secret); err != nil {
+		t.Fatalf("MutateUpdate: got error: %s", err)
+	}
+	if string(secret.Data["key1"]) != string(oldSecret.Data["key1"]) || len(warnings) != 0 {
+		t.Errorf("MutateUpdate: got invalid value %s (warnings: %v)", secret.Data["key1"], warnings)
+	}
+
+	// regenerate: the key is generated from the changed placeholder
+	oldSecret = secret.DeepCopy()
+	secret.Annotations[AnnotationKeyOnSpecChange] = "regenerate"
+	secret.Data["key1"] = []byte("%generate:password:length=32")
+	if err := w.MutateUpdate(context.TODO(), oldSecret, secret); err != nil {
+		t.Fatalf("MutateUpdate: got error: %s", err)
+	}
+	if len(secret.Data["key1"]) != 32 || getGeneratedKeys(secret)["key1"].Spec != "password:length=32" {
+		t.Errorf("MutateUpdate: key was not regenerated: %s", secret.Data["key1"])
+	}
+
+	// unchanged placeholders are kept
+	oldSecret = secret.DeepCopy()
+	secret.Data["key1"] = []byte("%generate:password:length=32")
+	if err := w.MutateUpdate(context.TODO(), oldSecret, secret); err != nil {
+		t.Fatalf("MutateUpdate: got error: %s", err)
+	}
+	if string(secret.Data["key1"]) != string(oldSecret.Data["key1"]) {
+		t.Error("MutateUpdate: existing value got changed")
+	}
+
+	// changed placeholders are validated
+	secret.Data["key1"] = []byte("%generate:password:length=foo")
+	if err := w.ValidateUpdate(context.TODO(), oldSecret, secret); err == nil {
+		t.Error("ValidateUpdate: expected error, but got none")
+	} else {
+		t.Logf("ok; got error: %s", err)
+	}
+}
+
+func TestHandleUpdateSecretWithRetainGenerated(t *testing.T) {
+	w := NewSecretWebhook()
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Annotations: map[string]string{AnnotationKeyRetainGenerated: "true"},
+		},
+		Data: map[string][]byte{
+			"key1": []byte("%generate"),
+			"key2": []byte("%generate:mtls"),
+			"key3": []byte("value"),
+		},
+	}
+	if err := w.MutateCreate(context.TODO(), secret); err != nil {
+		t.Fatalf("MutateCreate: got error: %s", err)
+	}
+	oldSecret := secret.DeepCopy()
+	secret = &corev1.Secret{
+		ObjectMeta: *oldSecret.ObjectMeta.DeepCopy(),
+		Data:       map[string][]byte{},
+	}
+	if err := w.MutateUpdate(context.TODO(), oldSecret, secret); err != nil {
+		t.Fatalf("MutateUpdate: got error: %s", err)
+	}
+	for _, k := range append([]string{"key1"}, mtlsKeys...) {
+		if string(secret.Data[k]) != string(oldSecret.Data[k]) {
+			t.Errorf("MutateUpdate: generated key was not restored: %s", k)
+		}
+	}
+	if _, ok := secret.Data["key3"]; ok {
+		t.Error("MutateUpdate: unmanaged key was restored")
+	}
+	if getGeneratedKeys(secret)["key2"] == nil {
+		t.Errorf("MutateUpdate: record of restored bundle got lost: %s", secret.Annotations[AnnotationKeyGenerated])
+	}
+
+	// without annotation, generated keys can be removed
+	delete(secret.Annotations, AnnotationKeyRetainGenerated)
+	oldSecret = secret.DeepCopy()
+	delete(secret.Data, "key1")
+	if err := w.MutateUpdate(context.TODO(), oldSecret, secret); err != nil {
+		t.Fatalf("MutateUpdate: got error: %s", err)
+	}
+	if _, ok := secret.Data["key1"]; ok {
+		t.Error("MutateUpdate: generated key was restored")
+	}
+}
+
+func TestHandleUpdateSecretWithLiterals(t *testing.T) {
+	var warnings []string
+	w := NewSecretWebhook(WithWarningHandler(func(ctx context.Context, secret *corev1.Secret, message string) {
+		warnings = append(warnings, message)
+	}))
+	secret := &corev1.Secret{
+		Data: map[string][]byte{
+			"id":       []byte("%%generate:uuid"),
+			"template": []byte("%%%generate:password"),
+			"password": []byte("%generate:password"),
+		},
+	}
+	if err := w.handleCreateSecret(context.TODO(), secret); err != nil {
+		t.Fatalf("handleCreateSecret: got errror: %s", err)
+	}
+	expected := map[string]string{"id": "%generate:uuid", "template": "%%generate:password"}
+
+	// unchanged literals are neither unescaped again, nor adopted, nor rotated
+	for _, rotate := range []string{"", "2026-10-18T00:00:00Z"} {
+		oldSecret := secret.DeepCopy()
+		if rotate != "" {
+			secret.Annotations[AnnotationKeyRotate] = rotate
+		}
+		if err := w.handleUpdateSecret(context.TODO(), secret, oldSecret); err != nil {
+			t.Fatalf("handleUpdateSecret: got errror: %s", err)
+		}
+		for k, v := range expected {
+			if s := string(secret.Data[k]); s != v {
+				t.Errorf("handleUpdateSecret: got invalid literal value of key %s: %s (expected: %s)", k, s, v)
+			}
+		}
+		if generated := getGeneratedKeys(secret); len(generated) != 1 || generated["password"] == nil {
+			t.Errorf("handleUpdateSecret: got invalid %s annotation: %s", AnnotationKeyGenerated, secret.Annotations[AnnotationKeyGenerated])
+		}
+		if len(warnings) != 0 {
+			t.Errorf("handleUpdateSecret: got unexpected warnings: %v", warnings)
+		}
+	}
+
+	// literals without record (e.g. written before literals were recorded) are not adopted either
+	oldSecret := &corev1.Secret{
+		Data: map[string][]byte{
+			"id": []byte("%generate:uuid"),
+		},
+	}
+	secret = oldSecret.DeepCopy()
+	if err := w.handleUpdateSecret(context.TODO(), secret, oldSecret); err != nil {
+		t.Fatalf("handleUpdateSecret: got errror: %s", err)
+	}
+	if s := string(secret.Data["id"]); s != "%generate:uuid" || len(getGeneratedKeys(secret)) != 0 {
+		t.Errorf("handleUpdateSecret: got invalid literal value: %s (annotations: %v)", s, secret.Annotations)
+	}
+
+	// placeholders for an existing literal are ignored (as for all existing keys), but the literal is not adopted
+	oldSecret = secret.DeepCopy()
+	secret.Data["id"] = []byte("%generate:uuid:encoding=hex")
+	if err := w.handleUpdateSecret(context.TODO(), secret, oldSecret); err != nil {
+		t.Fatalf("handleUpdateSecret: got errror: %s", err)
+	}
+	if s := string(secret.Data["id"]); s != "%generate:uuid" || len(getGeneratedKeys(secret)) != 0 {
+		t.Errorf("handleUpdateSecret: got invalid literal value: %s (annotations: %v)", s, secret.Annotations)
+	}
+}
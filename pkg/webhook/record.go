@@ -0,0 +1,255 @@
+/*
+SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company and secret-generator contributors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package webhook
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"slices"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/sap/secret-generator/pkg/generator"
+)
+
+const (
+	// AnnotationKeyGenerated records (as JSON) how the generated keys of a secret were produced; it is maintained by the webhook.
+	AnnotationKeyGenerated = "secret-generator.cs.sap.com/generated"
+	// AnnotationKeyErrors records (as JSON, by key) why keys could not be generated in soft-fail mode; it is maintained by the webhook.
+	AnnotationKeyErrors = "secret-generator.cs.sap.com/errors"
+	// AnnotationKeyLiterals records (as JSON list) the keys whose values were unescaped (such as %%generate:uuid to %generate:uuid),
+	// and must therefore not be interpreted as placeholders; it is maintained by the webhook.
+	AnnotationKeyLiterals = "secret-generator.cs.sap.com/literals"
+	// AnnotationKeyPlaceholders records (as JSON, by key) the placeholders of generated keys, as specified in the secret's manifest;
+	// it is maintained by the webhook in GitOps mode.
+	AnnotationKeyPlaceholders = "secret-generator.cs.sap.com/placeholders"
+)
+
+// record of a generated key (or bundle)
+type generatedKey struct {
+	// spec of the placeholder (without prefix), in normalized form
+	Spec string `json:"spec"`
+	// hash of the spec, see hashSpec()
+	Hash string `json:"hash,omitempty"`
+	// version of the generators which produced the value; not set for keys generated before versions were recorded
+	Version string `json:"version,omitempty"`
+	// keys produced by a bundle generator; empty for regular generators (which produce the key holding the placeholder)
+	Keys []string `json:"keys,omitempty"`
+	// time of generation; not set for keys generated before generation times were recorded
+	GeneratedAt *metav1.Time `json:"generatedAt,omitempty"`
+}
+
+// return a record of a key (or bundle) generated now
+func newGeneratedKey(format string, keys []string) *generatedKey {
+	now := metav1.Now()
+	spec := normalizeSpec(format)
+	return &generatedKey{Spec: spec, Hash: hashSpec(spec), Version: generator.Version(), Keys: keys, GeneratedAt: &now}
+}
+
+// return a record of an existing key whose generation was not recorded, assuming it was generated from format
+func adoptGeneratedKey(format string) *generatedKey {
+	spec := normalizeSpec(format)
+	return &generatedKey{Spec: spec, Hash: hashSpec(spec)}
+}
+
+// return the recorded generated keys of secret; an invalid annotation is ignored
+func getGeneratedKeys(secret metav1.Object) map[string]*generatedKey {
+	generated := make(map[string]*generatedKey)
+	if v, ok := secret.GetAnnotations()[AnnotationKeyGenerated]; ok {
+		if err := json.Unmarshal([]byte(v), &generated); err != nil {
+			return make(map[string]*generatedKey)
+		}
+	}
+	return generated
+}
+
+// store the records of generated keys in secret; records of keys which no longer exist are dropped
+func setGeneratedKeys(secret *corev1.Secret, generated map[string]*generatedKey) {
+	for _, k := range slices.Collect(maps.Keys(generated)) {
+		keys := generated[k].Keys
+		if len(keys) == 0 {
+			keys = []string{k}
+		}
+		if slices.ContainsFunc(keys, func(key string) bool { _, ok := secret.Data[key]; return !ok }) {
+			delete(generated, k)
+		}
+	}
+	if len(generated) == 0 {
+		delete(secret.Annotations, AnnotationKeyGenerated)
+		return
+	}
+	v, err := json.Marshal(generated)
+	if err != nil {
+		// cannot happen
+		panic(err)
+	}
+	if secret.Annotations == nil {
+		secret.Annotations = make(map[string]string)
+	}
+	secret.Annotations[AnnotationKeyGenerated] = string(v)
+}
+
+// return the normalized form of a placeholder spec
+func normalizeSpec(format string) string {
+	spec, err := generator.ParseSpec(format)
+	if err != nil {
+		return format
+	}
+	return spec.String()
+}
+
+// return the hash of a (normalized) placeholder spec, as sha256:<hex>
+func hashSpec(spec string) string {
+	return fmt.Sprintf("sha256:%x", sha256.Sum256([]byte(spec)))
+}
+
+// check if two placeholder specs are equivalent, i.e. refer to the same generator with the same arguments
+func (w *SecretWebhook) equalSpecs(format1 string, format2 string) bool {
+	g1, args1, err1 := w.registry.Parse(format1)
+	g2, args2, err2 := w.registry.Parse(format2)
+	if err1 != nil || err2 != nil {
+		return normalizeSpec(format1) == normalizeSpec(format2)
+	}
+	return g1.Name() == g2.Name() && maps.Equal(args1, args2)
+}
+
+// return the recorded placeholders of secret (by key); an invalid annotation is ignored
+func getPlaceholders(secret *corev1.Secret) map[string]string {
+	placeholders := make(map[string]string)
+	if v, ok := secret.Annotations[AnnotationKeyPlaceholders]; ok {
+		if err := json.Unmarshal([]byte(v), &placeholders); err != nil {
+			return make(map[string]string)
+		}
+	}
+	return placeholders
+}
+
+// store the placeholders of the generated keys in secret, if GitOps mode is enabled; otherwise the annotation is removed
+func (w *SecretWebhook) setPlaceholders(secret *corev1.Secret, placeholders map[string]string, generated map[string]*generatedKey) {
+	if !w.isGitOps(secret) {
+		delete(secret.Annotations, AnnotationKeyPlaceholders)
+		return
+	}
+	for k := range placeholders {
+		if _, ok := generated[k]; !ok {
+			delete(placeholders, k)
+		}
+	}
+	if len(placeholders) == 0 {
+		delete(secret.Annotations, AnnotationKeyPlaceholders)
+		return
+	}
+	v, err := json.Marshal(placeholders)
+	if err != nil {
+		// cannot happen
+		panic(err)
+	}
+	if secret.Annotations == nil {
+		secret.Annotations = make(map[string]string)
+	}
+	secret.Annotations[AnnotationKeyPlaceholders] = string(v)
+}
+
+// return the recorded literal keys of secret; an invalid annotation is ignored
+func getLiteralKeys(secret *corev1.Secret) map[string]bool {
+	literals := make(map[string]bool)
+	if v, ok := secret.Annotations[AnnotationKeyLiterals]; ok {
+		var keys []string
+		if err := json.Unmarshal([]byte(v), &keys); err != nil {
+			return literals
+		}
+		for _, k := range keys {
+			literals[k] = true
+		}
+	}
+	return literals
+}
+
+// store the literal keys in secret; keys which no longer exist are dropped, and the annotation is removed if there are none
+func setLiteralKeys(secret *corev1.Secret, literals map[string]bool) {
+	var keys []string
+	for _, k := range slices.Sorted(maps.Keys(literals)) {
+		if _, ok := secret.Data[k]; ok && literals[k] {
+			keys = append(keys, k)
+		}
+	}
+	if len(keys) == 0 {
+		delete(secret.Annotations, AnnotationKeyLiterals)
+		return
+	}
+	v, err := json.Marshal(keys)
+	if err != nil {
+		// cannot happen
+		panic(err)
+	}
+	if secret.Annotations == nil {
+		secret.Annotations = make(map[string]string)
+	}
+	secret.Annotations[AnnotationKeyLiterals] = string(v)
+}
+
+// return the recorded generation errors of secret (by key); an invalid annotation is ignored
+func getGenerationErrors(secret *corev1.Secret) map[string]string {
+	errs := make(map[string]string)
+	if v, ok := secret.Annotations[AnnotationKeyErrors]; ok {
+		if err := json.Unmarshal([]byte(v), &errs); err != nil {
+			return make(map[string]string)
+		}
+	}
+	return errs
+}
+
+// store the generation errors in secret; the annotation is removed if there are none
+func setGenerationErrors(secret *corev1.Secret, errs map[string]string) {
+	if len(errs) == 0 {
+		delete(secret.Annotations, AnnotationKeyErrors)
+		return
+	}
+	v, err := json.Marshal(errs)
+	if err != nil {
+		// cannot happen
+		panic(err)
+	}
+	if secret.Annotations == nil {
+		secret.Annotations = make(map[string]string)
+	}
+	secret.Annotations[AnnotationKeyErrors] = string(v)
+}
+
+// collects the errors of keys which could not be generated; in soft-fail mode, the errors are recorded in the secret
+// (and reported as warnings), and the affected keys keep their placeholder; otherwise the first error is returned
+type failures struct {
+	softFail bool
+	warn     func(message string)
+	errors   map[string]string
+}
+
+func (w *SecretWebhook) newFailures(ctx context.Context, secret *corev1.Secret) *failures {
+	return &failures{
+		softFail: w.isSoftFail(secret),
+		warn:     func(message string) { w.warn(ctx, secret, message) },
+		errors:   make(map[string]string),
+	}
+}
+
+// add the error of key; returns err, unless soft-fail mode is enabled
+func (f *failures) add(key string, err error) error {
+	if !f.softFail {
+		return err
+	}
+	f.errors[key] = err.Error()
+	f.warn(err.Error() + "; the placeholder is kept")
+	return nil
+}
+
+// record the collected errors in secret, replacing previously recorded errors
+func (f *failures) record(secret *corev1.Secret) {
+	setGenerationErrors(secret, f.errors)
+}
@@ -0,0 +1,54 @@
+/*
+SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company and secret-generator contributors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package webhook
+
+import (
+	"context"
+
+	admissionv1 "k8s.io/api/admission/v1"
+)
+
+type requestContextKey struct{}
+
+type warningsContextKey struct{}
+
+// warnings collected while processing an admission request
+type warnings struct {
+	messages []string
+}
+
+// NewContextWithRequest returns a copy of ctx carrying the admission request being processed; admission servers embedding the webhook
+// should pass such a context to the webhook's methods, such that dry runs and the requester are recognized (the handlers returned by
+// NewMutatingHandler() and NewValidatingHandler() do so).
+func NewContextWithRequest(ctx context.Context, req *admissionv1.AdmissionRequest) context.Context {
+	return context.WithValue(ctx, requestContextKey{}, req)
+}
+
+// RequestFromContext returns the admission request carried by ctx, or false, if there is none.
+func RequestFromContext(ctx context.Context) (*admissionv1.AdmissionRequest, bool) {
+	req, ok := ctx.Value(requestContextKey{}).(*admissionv1.AdmissionRequest)
+	return req, ok && req != nil
+}
+
+// check if the admission request being processed is a dry run; in that case, nothing must be persisted
+// (besides the secret itself, which the api server does not persist either)
+func isDryRun(ctx context.Context) bool {
+	req, ok := RequestFromContext(ctx)
+	return ok && req.DryRun != nil && *req.DryRun
+}
+
+// return a copy of ctx collecting the warnings passed to the default warning handler
+func newContextWithWarnings(ctx context.Context) (context.Context, *warnings) {
+	collected := &warnings{}
+	return context.WithValue(ctx, warningsContextKey{}, collected), collected
+}
+
+// add a warning to the warnings collected in ctx, if any
+func addWarning(ctx context.Context, message string) {
+	if collected, ok := ctx.Value(warningsContextKey{}).(*warnings); ok {
+		collected.messages = append(collected.messages, message)
+	}
+}
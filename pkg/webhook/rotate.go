@@ -0,0 +1,229 @@
+/*
+SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company and secret-generator contributors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package webhook
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/sap/secret-generator/pkg/generator"
+)
+
+const (
+	// AnnotationKeyRotate requests the rotation of generated keys whenever its value changes; the value is a comma-separated list
+	// of keys to be rotated, and optionally of RFC 3339 timestamps (which are ignored, except for changing the value); if no keys are listed,
+	// all keys produced by rotatable generators (i.e. credentials, such as passwords or keys) are rotated.
+	AnnotationKeyRotate = "secret-generator.cs.sap.com/rotate"
+	// AnnotationKeyMaxAge sets the maximum age of generated keys, after which the rotation controller requests their rotation; the value is
+	// a duration (Go duration or number of days, such as 90d) applying to all generated keys, or a comma-separated list of <key>=<duration> items,
+	// optionally combined with a default duration.
+	AnnotationKeyMaxAge = "secret-generator.cs.sap.com/max-age"
+	// AnnotationKeyRollover sets how existing values are handled on rotation: replace (default), previous (keep the existing value
+	// as <key>.previous), or next (stage the new value as <key>.next, until it is promoted).
+	AnnotationKeyRollover = "secret-generator.cs.sap.com/rollover"
+	// AnnotationKeyPromote requests the promotion of staged values (<key>.next) whenever its value changes; the value is interpreted
+	// like the value of the rotate annotation.
+	AnnotationKeyPromote = "secret-generator.cs.sap.com/promote"
+)
+
+const (
+	rolloverReplace  = "replace"
+	rolloverPrevious = "previous"
+	rolloverNext     = "next"
+	suffixPrevious   = ".previous"
+	suffixNext       = ".next"
+)
+
+// return the (recorded) generated keys of oldSecret which are to be rotated, because the rotate annotation was changed by the update;
+// bundles are selected by the key holding the placeholder, or by any of the produced keys; keys removed by the update are not rotated;
+// if no keys are listed, only keys of rotatable (i.e. credential) generators are rotated;
+// in addition, the listed keys which do not refer to a generated key are returned
+func (w *SecretWebhook) getRotatedKeys(secret *corev1.Secret, oldSecret *corev1.Secret, generated map[string]*generatedKey) (map[string]bool, []string) {
+	return getSelectedKeys(AnnotationKeyRotate, secret, oldSecret, generated, w.registry.IsRotatable)
+}
+
+// return the (recorded) generated keys selected by the given annotation (such as the rotate annotation), if it was changed by the update,
+// as well as the listed keys which do not refer to a generated key; if no keys are listed, the generated keys whose spec satisfies
+// selectAll (or all generated keys, if selectAll is nil) are selected
+func getSelectedKeys(annotation string, secret *corev1.Secret, oldSecret *corev1.Secret, generated map[string]*generatedKey, selectAll func(spec string) bool) (map[string]bool, []string) {
+	selected := make(map[string]bool)
+	value, ok := secret.Annotations[annotation]
+	if !ok || value == oldSecret.Annotations[annotation] {
+		return selected, nil
+	}
+	var keys []string
+	for _, item := range strings.Split(value, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		if _, err := time.Parse(time.RFC3339, item); err == nil {
+			continue
+		}
+		keys = append(keys, item)
+	}
+	matched := make(map[string]bool)
+	for _, k := range slices.Sorted(maps.Keys(generated)) {
+		record := generated[k]
+		recordKeys := append([]string{k}, record.Keys...)
+		if !slices.ContainsFunc(recordKeys, func(key string) bool { _, ok := secret.Data[key]; return ok }) {
+			continue
+		}
+		if len(keys) == 0 {
+			if selectAll == nil || selectAll(record.Spec) {
+				selected[k] = true
+			}
+			continue
+		}
+		for _, key := range keys {
+			if slices.Contains(recordKeys, key) {
+				selected[k] = true
+				matched[key] = true
+			}
+		}
+	}
+	var unknown []string
+	for _, key := range keys {
+		if !matched[key] {
+			unknown = append(unknown, key)
+		}
+	}
+	return selected, unknown
+}
+
+// generate the values of a recorded key (or bundle) anew, replacing the existing values (according to the rollover mode)
+func (w *SecretWebhook) rotateKey(ctx context.Context, secret *corev1.Secret, oldSecret *corev1.Secret, key string, record *generatedKey) error {
+	if len(record.Keys) > 0 {
+		generatedValues, err := w.generateBundle(ctx, secret, key, record.Spec)
+		if err != nil {
+			return errors.Wrapf(err, "error rotating values for key '%s'", key)
+		}
+		delete(secret.Data, key)
+		setRotatedValues(secret, oldSecret, generatedValues)
+		*record = *newGeneratedKey(record.Spec, slices.Sorted(maps.Keys(generatedValues)))
+		return nil
+	}
+	generatedValue, err := w.generateValue(ctx, secret, key, record.Spec)
+	if err != nil {
+		return errors.Wrapf(err, "error rotating value for key '%s'", key)
+	}
+	setRotatedValues(secret, oldSecret, generator.Values{key: generatedValue})
+	*record = *newGeneratedKey(record.Spec, nil)
+	return nil
+}
+
+// store rotated values in secret, according to its rollover mode: the existing values (of oldSecret) are either replaced (default),
+// or kept as <key>.previous, or the new values are staged as <key>.next (until they are promoted), keeping the existing values
+func setRotatedValues(secret *corev1.Secret, oldSecret *corev1.Secret, values generator.Values) {
+	mode := secret.Annotations[AnnotationKeyRollover]
+	for k, v := range values {
+		oldValue, ok := oldSecret.Data[k]
+		switch {
+		case ok && mode == rolloverPrevious:
+			secret.Data[k+suffixPrevious] = oldValue
+			secret.Data[k] = []byte(v)
+		case ok && mode == rolloverNext:
+			secret.Data[k] = oldValue
+			secret.Data[k+suffixNext] = []byte(v)
+		default:
+			secret.Data[k] = []byte(v)
+		}
+	}
+}
+
+// promote the staged values of the generated keys selected by the promote annotation (if it was changed by the update),
+// i.e. replace the values of the keys by the values of <key>.next, and remove the latter
+func (w *SecretWebhook) promoteKeys(ctx context.Context, secret *corev1.Secret, oldSecret *corev1.Secret, generated map[string]*generatedKey) {
+	promoted, unknown := getSelectedKeys(AnnotationKeyPromote, secret, oldSecret, generated, nil)
+	for _, k := range unknown {
+		w.warn(ctx, secret, fmt.Sprintf("key '%s' is not a generated key, and therefore not promoted", k))
+	}
+	for _, k := range slices.Sorted(maps.Keys(promoted)) {
+		keys := generated[k].Keys
+		if len(keys) == 0 {
+			keys = []string{k}
+		}
+		for _, key := range keys {
+			if v, ok := secret.Data[key+suffixNext]; ok {
+				secret.Data[key] = v
+				delete(secret.Data, key+suffixNext)
+			}
+		}
+	}
+}
+
+// GetExpiredKeys returns the generated keys of secret which exceed their maximum age (according to the max-age annotation)
+// at the given time, as well as the time when the next key expires (zero, if no other key expires); keys whose generation time
+// was not recorded are considered as old as the secret. The default maximum age only applies to keys of rotatable generators
+// (looked up in registry, or in generator.DefaultRegistry if registry is nil); keys listed explicitly expire regardless of their generator.
+// Only the metadata of secret is used.
+func GetExpiredKeys(secret metav1.Object, registry *generator.Registry, now time.Time) ([]string, time.Time, error) {
+	if registry == nil {
+		registry = generator.DefaultRegistry
+	}
+	value, ok := secret.GetAnnotations()[AnnotationKeyMaxAge]
+	if !ok {
+		return nil, time.Time{}, nil
+	}
+	var defaultMaxAge time.Duration
+	maxAges := make(map[string]time.Duration)
+	for _, item := range strings.Split(value, ",") {
+		key, duration, ok := strings.Cut(strings.TrimSpace(item), "=")
+		if !ok {
+			key, duration = "", key
+		}
+		d, err := generator.ParseDuration(duration)
+		if err == nil && d <= 0 {
+			err = fmt.Errorf("duration must be positive")
+		}
+		if err != nil {
+			return nil, time.Time{}, errors.Wrapf(err, "invalid value of annotation %s: %s", AnnotationKeyMaxAge, item)
+		}
+		if key == "" {
+			defaultMaxAge = d
+		} else {
+			maxAges[key] = d
+		}
+	}
+	var expired []string
+	var next time.Time
+	generated := getGeneratedKeys(secret)
+	for _, k := range slices.Sorted(maps.Keys(generated)) {
+		record := generated[k]
+		var maxAge time.Duration
+		if registry.IsRotatable(record.Spec) {
+			maxAge = defaultMaxAge
+		}
+		for _, key := range append([]string{k}, record.Keys...) {
+			if d, ok := maxAges[key]; ok {
+				maxAge = d
+				break
+			}
+		}
+		if maxAge == 0 {
+			continue
+		}
+		generatedAt := secret.GetCreationTimestamp().Time
+		if record.GeneratedAt != nil {
+			generatedAt = record.GeneratedAt.Time
+		}
+		if expiresAt := generatedAt.Add(maxAge); !expiresAt.After(now) {
+			expired = append(expired, k)
+		} else if next.IsZero() || expiresAt.Before(next) {
+			next = expiresAt
+		}
+	}
+	return expired, next, nil
+}
@@ -0,0 +1,223 @@
+/*
+SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company and secret-generator contributors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package webhook
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestRotate(t *testing.T) {
+	w := NewSecretWebhook()
+	secret := &corev1.Secret{
+		Data: map[string][]byte{
+			"password": []byte("%generate:password:length=16"),
+			"token":    []byte("%generate"),
+			"tls":      []byte("%generate:mtls"),
+			"mac":      []byte("%generate:mac"),
+			"other":    []byte("value"),
+		},
+	}
+	if err := w.MutateCreate(context.TODO(), secret); err != nil {
+		t.Fatalf("got error: %s", err)
+	}
+
+	// rotate selected keys (without placeholder, as e.g. with kubectl annotate)
+	oldSecret := secret.DeepCopy()
+	secret.Annotations[AnnotationKeyRotate] = "password,server.crt"
+	if err := w.MutateUpdate(context.TODO(), oldSecret, secret); err != nil {
+		t.Fatalf("got error: %s", err)
+	}
+	if s := string(secret.Data["password"]); s == string(oldSecret.Data["password"]) || len(s) != 16 {
+		t.Errorf("key was not rotated: %s", s)
+	}
+	for _, k := range mtlsKeys {
+		if string(secret.Data[k]) == string(oldSecret.Data[k]) || len(secret.Data[k]) == 0 {
+			t.Errorf("bundle key was not rotated: %s", k)
+		}
+	}
+	if string(secret.Data["token"]) != string(oldSecret.Data["token"]) || string(secret.Data["other"]) != "value" {
+		t.Error("other keys got changed")
+	}
+
+	// unchanged annotation does not rotate again
+	oldSecret = secret.DeepCopy()
+	if err := w.MutateUpdate(context.TODO(), oldSecret, secret); err != nil {
+		t.Fatalf("got error: %s", err)
+	}
+	if string(secret.Data["password"]) != string(oldSecret.Data["password"]) {
+		t.Error("key was rotated, although annotation did not change")
+	}
+
+	// timestamp rotates all keys of rotatable generators; placeholders given in the update are used
+	oldSecret = secret.DeepCopy()
+	secret.Annotations[AnnotationKeyRotate] = "2026-10-18T12:00:00Z"
+	secret.Data["password"] = []byte("%generate:password:length=32")
+	if err := w.MutateUpdate(context.TODO(), oldSecret, secret); err != nil {
+		t.Fatalf("got error: %s", err)
+	}
+	if s := string(secret.Data["password"]); len(s) != 32 {
+		t.Errorf("key was not rotated with new placeholder: %s", s)
+	}
+	if string(secret.Data["token"]) == string(oldSecret.Data["token"]) || string(secret.Data["ca.crt"]) == string(oldSecret.Data["ca.crt"]) {
+		t.Error("keys were not rotated")
+	}
+	if string(secret.Data["other"]) != "value" {
+		t.Error("unmanaged key got changed")
+	}
+	if string(secret.Data["mac"]) != string(oldSecret.Data["mac"]) {
+		t.Error("key of non-rotatable generator was rotated")
+	}
+	if record := getGeneratedKeys(secret)["password"]; record == nil || record.Spec != "password:length=32" || record.GeneratedAt == nil {
+		t.Errorf("got invalid record: %v", record)
+	}
+
+	// keys of non-rotatable generators are rotated if listed explicitly
+	oldSecret = secret.DeepCopy()
+	secret.Annotations[AnnotationKeyRotate] = "mac,2026-10-18T13:00:00Z"
+	if err := w.MutateUpdate(context.TODO(), oldSecret, secret); err != nil {
+		t.Fatalf("got error: %s", err)
+	}
+	if string(secret.Data["mac"]) == string(oldSecret.Data["mac"]) {
+		t.Error("key was not rotated")
+	}
+	if string(secret.Data["password"]) != string(oldSecret.Data["password"]) {
+		t.Error("other keys got changed")
+	}
+}
+
+func TestRotateS3Credentials(t *testing.T) {
+	w := NewSecretWebhook()
+	secret := &corev1.Secret{
+		Data: map[string][]byte{
+			"s3": []byte("%generate:s3:key_prefix=backup-"),
+		},
+	}
+	if err := w.MutateCreate(context.TODO(), secret); err != nil {
+		t.Fatalf("got error: %s", err)
+	}
+	keys := []string{"backup-access-key-id", "backup-secret-access-key"}
+	if len(secret.Data) != len(keys) {
+		t.Fatalf("got invalid number of keys: %d", len(secret.Data))
+	}
+
+	// rotating the secret access key rotates the access key id as well
+	oldSecret := secret.DeepCopy()
+	secret.Annotations[AnnotationKeyRotate] = "backup-secret-access-key"
+	if err := w.MutateUpdate(context.TODO(), oldSecret, secret); err != nil {
+		t.Fatalf("got error: %s", err)
+	}
+	for _, k := range keys {
+		if string(secret.Data[k]) == string(oldSecret.Data[k]) || len(secret.Data[k]) == 0 {
+			t.Errorf("bundle key was not rotated: %s", k)
+		}
+	}
+}
+
+func TestGetExpiredKeys(t *testing.T) {
+	now := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			CreationTimestamp: metav1.NewTime(now.Add(-48 * time.Hour)),
+			Annotations: map[string]string{
+				AnnotationKeyMaxAge:    "ca.crt=1d,password=30d",
+				AnnotationKeyGenerated: `{"password":{"spec":"password","generatedAt":"2026-10-01T00:00:00Z"},"token":{"spec":"uuid"},"id":{"spec":"mac"},"tls":{"spec":"mtls","keys":["ca.crt","server.crt"]}}`,
+			},
+		},
+	}
+	expired, next, err := GetExpiredKeys(secret, nil, now)
+	if err != nil {
+		t.Fatalf("got error: %s", err)
+	}
+	// token has no max-age; tls has no recorded generation time, and is as old as the secret
+	if !slices.Equal(expired, []string{"tls"}) || !next.Equal(time.Date(2026, 10, 31, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("got invalid expired keys: %v (next: %s)", expired, next)
+	}
+
+	// the default max-age only applies to rotatable generators, unless keys are listed explicitly
+	secret.Annotations[AnnotationKeyMaxAge] = "1d"
+	if expired, _, err := GetExpiredKeys(secret, nil, now); err != nil {
+		t.Fatalf("got error: %s", err)
+	} else if !slices.Equal(expired, []string{"password", "tls", "token"}) {
+		t.Errorf("got invalid expired keys: %v", expired)
+	}
+	secret.Annotations[AnnotationKeyMaxAge] = "1d,id=1d"
+	if expired, _, err := GetExpiredKeys(secret, nil, now); err != nil {
+		t.Fatalf("got error: %s", err)
+	} else if !slices.Equal(expired, []string{"id", "password", "tls", "token"}) {
+		t.Errorf("got invalid expired keys: %v", expired)
+	}
+
+	secret.Annotations[AnnotationKeyMaxAge] = "password=0d"
+	if _, _, err := GetExpiredKeys(secret, nil, now); err == nil {
+		t.Error("expected error, but got none")
+	} else {
+		t.Logf("ok; got error: %s", err)
+	}
+}
+
+func TestRotateWithRollover(t *testing.T) {
+	w := NewSecretWebhook()
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Annotations: map[string]string{AnnotationKeyRollover: "previous"},
+		},
+		Data: map[string][]byte{
+			"password": []byte("%generate"),
+		},
+	}
+	if err := w.MutateCreate(context.TODO(), secret); err != nil {
+		t.Fatalf("got error: %s", err)
+	}
+	if _, ok := secret.Data["password.previous"]; ok {
+		t.Error("got previous value on creation")
+	}
+
+	// previous: the existing value is kept as password.previous
+	oldSecret := secret.DeepCopy()
+	secret.Annotations[AnnotationKeyRotate] = "2026-10-18T12:00:00Z"
+	if err := w.MutateUpdate(context.TODO(), oldSecret, secret); err != nil {
+		t.Fatalf("got error: %s", err)
+	}
+	if string(secret.Data["password.previous"]) != string(oldSecret.Data["password"]) || string(secret.Data["password"]) == string(oldSecret.Data["password"]) {
+		t.Errorf("got invalid data after rotation: %v", secret.Data)
+	}
+
+	// next: the new value is staged as password.next
+	oldSecret = secret.DeepCopy()
+	secret.Annotations[AnnotationKeyRollover] = "next"
+	secret.Annotations[AnnotationKeyRotate] = "password,2026-10-18T13:00:00Z"
+	if err := w.MutateUpdate(context.TODO(), oldSecret, secret); err != nil {
+		t.Fatalf("got error: %s", err)
+	}
+	next := string(secret.Data["password.next"])
+	if string(secret.Data["password"]) != string(oldSecret.Data["password"]) || len(next) != 32 || next == string(oldSecret.Data["password"]) {
+		t.Errorf("got invalid data after rotation: %v", secret.Data)
+	}
+
+	// promotion replaces the value by the staged one
+	oldSecret = secret.DeepCopy()
+	secret.Annotations[AnnotationKeyPromote] = "2026-10-18T14:00:00Z"
+	if err := w.MutateUpdate(context.TODO(), oldSecret, secret); err != nil {
+		t.Fatalf("got error: %s", err)
+	}
+	if _, ok := secret.Data["password.next"]; ok || string(secret.Data["password"]) != next {
+		t.Errorf("got invalid data after promotion: %v", secret.Data)
+	}
+
+	// invalid rollover mode
+	secret.Annotations[AnnotationKeyRollover] = "both"
+	if err := w.ValidateUpdate(context.TODO(), secret, secret); err == nil {
+		t.Error("expected error, but got none")
+	} else {
+		t.Logf("ok; got error: %s", err)
+	}
+}
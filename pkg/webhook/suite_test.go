@@ -8,6 +8,7 @@ package webhook_test
 import (
 	"context"
 	"crypto/tls"
+	"crypto/x509"
 	"encoding/base32"
 	"encoding/base64"
 	"encoding/hex"
@@ -24,17 +25,16 @@ import (
 
 	admissionv1 "k8s.io/api/admissionregistration/v1"
 	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
 	"sigs.k8s.io/controller-runtime/pkg/envtest"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+	ctrlwebhook "sigs.k8s.io/controller-runtime/pkg/webhook"
 
-	"github.com/sap/admission-webhook-runtime/pkg/admission"
-
-	"github.com/sap/secret-generator/internal/webhook"
+	"github.com/sap/secret-generator/pkg/webhook"
 )
 
 func TestWebhook(t *testing.T) {
@@ -51,6 +51,9 @@ var clientset kubernetes.Interface
 
 const testingNamespace = "testing"
 
+// secrets with this label are not passed to the mutating webhook (but still to the validating webhook)
+const skipMutationLabel = "secret-generator.test/skip-mutation"
+
 var _ = BeforeSuite(func() {
 	log.SetLogger(zap.New(zap.WriteTo(GinkgoWriter), zap.UseDevMode(true)))
 
@@ -63,6 +66,9 @@ var _ = BeforeSuite(func() {
 			MutatingWebhooks: []*admissionv1.MutatingWebhookConfiguration{
 				buildMutatingWebhookConfiguration(),
 			},
+			ValidatingWebhooks: []*admissionv1.ValidatingWebhookConfiguration{
+				buildValidatingWebhookConfiguration(),
+			},
 		},
 	}
 	cfg, err = testEnv.Start()
@@ -74,26 +80,19 @@ var _ = BeforeSuite(func() {
 	clientset, err = kubernetes.NewForConfig(cfg)
 	Expect(err).NotTo(HaveOccurred())
 
-	By("initializing webhook scheme")
-	scheme := runtime.NewScheme()
-	err = corev1.AddToScheme(scheme)
-	Expect(err).NotTo(HaveOccurred())
-
-	By("registering webhook")
-	err = admission.RegisterMutatingWebhook[*corev1.Secret](webhook.NewSecretWebhook(), scheme, log.Log)
-	Expect(err).NotTo(HaveOccurred())
-
 	By("starting webhook server")
+	server := ctrlwebhook.NewServer(ctrlwebhook.Options{
+		Host:    webhookInstallOptions.LocalServingHost,
+		Port:    webhookInstallOptions.LocalServingPort,
+		CertDir: webhookInstallOptions.LocalServingCertDir,
+	})
+	server.Register("/core/v1/secret/mutate", webhook.NewMutatingHandler(webhook.NewSecretWebhook(webhook.WithClient(clientset))))
+	server.Register("/core/v1/secret/validate", webhook.NewValidatingHandler(webhook.NewSecretWebhook(webhook.WithClient(clientset))))
 	threads.Add(1)
 	go func() {
 		defer threads.Done()
 		defer GinkgoRecover()
-		options := &admission.ServeOptions{
-			BindAddress: fmt.Sprintf("%s:%d", webhookInstallOptions.LocalServingHost, webhookInstallOptions.LocalServingPort),
-			CertFile:    webhookInstallOptions.LocalServingCertDir + "/tls.crt",
-			KeyFile:     webhookInstallOptions.LocalServingCertDir + "/tls.key",
-		}
-		err := admission.Serve(ctx, options)
+		err := server.Start(ctx)
 		Expect(err).NotTo(HaveOccurred())
 	}()
 
@@ -229,6 +228,76 @@ var _ = Describe("Create secrets", func() {
 		_, err = hex.DecodeString(string(secret.Data["hexPasswordKey"]))
 		Expect(err).NotTo(HaveOccurred())
 	})
+
+	It("should generate a consistent mtls bundle", func() {
+		secret := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				GenerateName: "test-",
+			},
+			StringData: map[string]string{
+				"regularKey": "regularValue",
+				"tls":        "%generate:mtls:dns_names=db,db.testing.svc",
+			},
+		}
+
+		secret, err = clientset.CoreV1().Secrets(testingNamespace).Create(ctx, secret, metav1.CreateOptions{})
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(secret.Data).To(HaveKeyWithValue("regularKey", []byte("regularValue")))
+		Expect(secret.Data).NotTo(HaveKey("tls"))
+
+		roots := x509.NewCertPool()
+		Expect(roots.AppendCertsFromPEM(secret.Data["ca.crt"])).To(BeTrue())
+		for _, name := range []string{"server", "client"} {
+			keyPair, err := tls.X509KeyPair(secret.Data[name+".crt"], secret.Data[name+".key"])
+			Expect(err).NotTo(HaveOccurred())
+			_, err = keyPair.Leaf.Verify(x509.VerifyOptions{Roots: roots, KeyUsages: []x509.ExtKeyUsage{x509.ExtKeyUsageAny}})
+			Expect(err).NotTo(HaveOccurred())
+		}
+	})
+})
+
+var _ = Describe("Validate secrets", func() {
+	It("should report all invalid keys at once", func() {
+		secret := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				GenerateName: "test-",
+				Labels:       map[string]string{skipMutationLabel: "true"},
+			},
+			StringData: map[string]string{
+				"regularKey":  "regularValue",
+				"formatKey":   "%generate:foobar",
+				"argumentKey": "%generate:password:length=foo",
+				"syntaxKey":   "%generate:password:length=8;",
+				"uuidKey":     "%generate:uuid",
+			},
+		}
+
+		_, err := clientset.CoreV1().Secrets(testingNamespace).Create(ctx, secret, metav1.CreateOptions{})
+		Expect(apierrors.IsInvalid(err)).To(BeTrue(), "expected invalid error, but got: %v", err)
+		var fields []string
+		for _, cause := range err.(apierrors.APIStatus).Status().Details.Causes {
+			fields = append(fields, cause.Field)
+		}
+		Expect(fields).To(ConsistOf("data[formatKey]", "data[argumentKey]", "data[syntaxKey]"))
+	})
+
+	It("should admit valid secrets", func() {
+		secret := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				GenerateName: "test-",
+				Labels:       map[string]string{skipMutationLabel: "true"},
+			},
+			StringData: map[string]string{
+				"regularKey": "regularValue",
+				"uuidKey":    "%generate:uuid",
+			},
+		}
+
+		secret, err := clientset.CoreV1().Secrets(testingNamespace).Create(ctx, secret, metav1.CreateOptions{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(secret.Data).To(HaveKeyWithValue("uuidKey", []byte("%generate:uuid")))
+	})
 })
 
 var _ = Describe("Update secrets", func() {
@@ -295,6 +364,47 @@ func buildMutatingWebhookConfiguration() *admissionv1.MutatingWebhookConfigurati
 					"kubernetes.io/metadata.name": testingNamespace,
 				},
 			},
+			ObjectSelector: &metav1.LabelSelector{
+				MatchExpressions: []metav1.LabelSelectorRequirement{{
+					Key:      skipMutationLabel,
+					Operator: metav1.LabelSelectorOpDoesNotExist,
+				}},
+			},
+			SideEffects: &[]admissionv1.SideEffectClass{admissionv1.SideEffectClassNoneOnDryRun}[0],
+		}},
+	}
+}
+
+// assemble validatingwebhookconfiguration descriptor
+func buildValidatingWebhookConfiguration() *admissionv1.ValidatingWebhookConfiguration {
+	return &admissionv1.ValidatingWebhookConfiguration{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "validate",
+		},
+		Webhooks: []admissionv1.ValidatingWebhook{{
+			Name:                    "validate-secrets.test.local",
+			AdmissionReviewVersions: []string{"v1"},
+			ClientConfig: admissionv1.WebhookClientConfig{
+				Service: &admissionv1.ServiceReference{
+					Path: &[]string{"/core/v1/secret/validate"}[0],
+				},
+			},
+			Rules: []admissionv1.RuleWithOperations{{
+				Operations: []admissionv1.OperationType{
+					admissionv1.Create,
+					admissionv1.Update,
+				},
+				Rule: admissionv1.Rule{
+					APIGroups:   []string{""},
+					APIVersions: []string{"v1"},
+					Resources:   []string{"secrets"},
+				},
+			}},
+			NamespaceSelector: &metav1.LabelSelector{
+				MatchLabels: map[string]string{
+					"kubernetes.io/metadata.name": testingNamespace,
+				},
+			},
 			SideEffects: &[]admissionv1.SideEffectClass{admissionv1.SideEffectClassNone}[0],
 		}},
 	}
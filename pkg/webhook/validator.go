@@ -0,0 +1,97 @@
+/*
+SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company and secret-generator contributors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package webhook
+
+import (
+	"bytes"
+	"fmt"
+	"maps"
+	"slices"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	"github.com/sap/secret-generator/pkg/generator"
+)
+
+// check all placeholders of secret which would be generated (i.e. on updates, placeholders of kept keys are not checked);
+// oldSecret is nil on creation; all problems are returned at once, as field errors of an Invalid status error;
+// in soft-fail mode, placeholders whose generation errors are recorded in secret are accepted; so are literal keys
+// recorded in secret (which the validating webhook sees after mutation, i.e. after escaped values were unescaped)
+func (w *SecretWebhook) validateSecret(secret *corev1.Secret, oldSecret *corev1.Secret) error {
+	prefix := w.getPrefix(secret)
+	literals := getLiteralKeys(secret)
+	failed := make(map[string]string)
+	if w.isSoftFail(secret) {
+		failed = getGenerationErrors(secret)
+	}
+	var oldErrors map[string]string
+	var generated map[string]*generatedKey
+	var rotated map[string]bool
+	if oldSecret != nil {
+		oldErrors = getGenerationErrors(oldSecret)
+		generated = getGeneratedKeys(oldSecret)
+		rotated, _ = w.getRotatedKeys(secret, oldSecret, generated)
+	}
+	var errs field.ErrorList
+	// keys produced by the bundle placeholders seen so far, and the placeholder key producing them
+	bundleKeys := make(map[string]string)
+	for _, k := range slices.Sorted(maps.Keys(secret.Data)) {
+		value := string(secret.Data[k])
+		if literals[k] {
+			continue
+		}
+		if _, ok := generator.Unescape(value, prefix); ok {
+			continue
+		}
+		format, ok := generator.ParseValue(value, prefix)
+		if !ok {
+			continue
+		}
+		if _, ok := failed[k]; ok {
+			continue
+		}
+		// unchanged values are kept by the mutating webhook, even if they look like placeholders
+		if oldSecret != nil && isKeptKey(oldSecret, oldErrors, k) && bytes.Equal(secret.Data[k], oldSecret.Data[k]) {
+			continue
+		}
+		if oldSecret != nil && isKeptKey(oldSecret, oldErrors, k) && !rotated[k] && !w.isRegeneratedOnSpecChange(secret, k, format, generated) && !w.registry.IsBundle(format) {
+			continue
+		}
+		path := field.NewPath("data").Key(k)
+		g, args, err := w.registry.Parse(format)
+		if err != nil {
+			errs = append(errs, field.Invalid(path, value, err.Error()))
+			continue
+		}
+		if bundle, ok := g.(generator.BundleGenerator); ok {
+			for _, bk := range bundle.Keys(args) {
+				if _, ok := secret.Data[bk]; ok && oldSecret == nil {
+					errs = append(errs, field.Invalid(path, value, fmt.Sprintf("key '%s' already exists", bk)))
+				}
+				if other, ok := bundleKeys[bk]; ok {
+					errs = append(errs, field.Invalid(path, value, fmt.Sprintf("key '%s' is also generated by key '%s' (bundles in the same secret need distinct key prefixes)", bk, other)))
+				}
+				bundleKeys[bk] = k
+			}
+		}
+	}
+	if v, ok := secret.Annotations[AnnotationKeyRollover]; ok && !slices.Contains([]string{rolloverReplace, rolloverPrevious, rolloverNext}, v) {
+		errs = append(errs, field.NotSupported(field.NewPath("metadata", "annotations").Key(AnnotationKeyRollover), v, []string{rolloverReplace, rolloverPrevious, rolloverNext}))
+	}
+	if v, ok := secret.Annotations[AnnotationKeyOnSpecChange]; ok && !slices.Contains([]string{onSpecChangeWarn, onSpecChangeKeep, onSpecChangeRegenerate}, v) {
+		errs = append(errs, field.NotSupported(field.NewPath("metadata", "annotations").Key(AnnotationKeyOnSpecChange), v, []string{onSpecChangeWarn, onSpecChangeKeep, onSpecChangeRegenerate}))
+	}
+	if _, err := getHistoryLimit(secret); err != nil {
+		errs = append(errs, field.Invalid(field.NewPath("metadata", "annotations").Key(AnnotationKeyHistory), secret.Annotations[AnnotationKeyHistory], "must be a positive number"))
+	}
+	if len(errs) > 0 {
+		return apierrors.NewInvalid(schema.GroupKind{Kind: "Secret"}, secret.Name, errs)
+	}
+	return nil
+}
@@ -0,0 +1,149 @@
+/*
+SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company and secret-generator contributors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package webhook
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestValidateCreate(t *testing.T) {
+	w := NewSecretWebhook()
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "test",
+		},
+		Data: map[string][]byte{
+			"key1":   []byte("%generate:foobar"),
+			"key2":   []byte("%generate:password:length=foo"),
+			"key3":   []byte("%generate:password:length=8;"),
+			"key4":   []byte("%generate:uuid"),
+			"key5":   []byte("%%generate:foobar"),
+			"key6":   []byte("%generate:mtls"),
+			"ca.crt": []byte("value"),
+		},
+	}
+	err := w.ValidateCreate(context.TODO(), secret)
+	if !apierrors.IsInvalid(err) {
+		t.Fatalf("expected invalid error, but got: %v", err)
+	}
+	t.Logf("ok; got error: %s", err)
+	var fields []string
+	for _, cause := range err.(apierrors.APIStatus).Status().Details.Causes {
+		fields = append(fields, cause.Field)
+	}
+	if expected := []string{"data[key1]", "data[key2]", "data[key3]", "data[key6]"}; !slices.Equal(fields, expected) {
+		t.Errorf("got invalid causes: %v (expected: %v)", fields, expected)
+	}
+
+	// mutation reports the same errors
+	if err := w.MutateCreate(context.TODO(), secret); !apierrors.IsInvalid(err) {
+		t.Errorf("expected invalid error, but got: %v", err)
+	}
+
+	delete(secret.Data, "key1")
+	delete(secret.Data, "key2")
+	delete(secret.Data, "key3")
+	delete(secret.Data, "ca.crt")
+	if err := w.ValidateCreate(context.TODO(), secret); err != nil {
+		t.Errorf("got error: %s", err)
+	}
+}
+
+func TestValidateUpdate(t *testing.T) {
+	w := NewSecretWebhook()
+	secret := &corev1.Secret{
+		Data: map[string][]byte{
+			"key1": []byte("%generate:foobar"),
+			"key2": []byte("%generate:password:length=foo"),
+		},
+	}
+	oldSecret := &corev1.Secret{
+		Data: map[string][]byte{
+			"key1": []byte("value"),
+		},
+	}
+	err := w.ValidateUpdate(context.TODO(), oldSecret, secret)
+	if !apierrors.IsInvalid(err) {
+		t.Fatalf("expected invalid error, but got: %v", err)
+	}
+	// key1 is kept, and therefore not validated
+	if causes := err.(apierrors.APIStatus).Status().Details.Causes; len(causes) != 1 || causes[0].Field != "data[key2]" {
+		t.Errorf("got invalid causes: %v", causes)
+	}
+}
+
+func TestValidateCreateAfterMutation(t *testing.T) {
+	w := NewSecretWebhook()
+	secret := &corev1.Secret{
+		Data: map[string][]byte{
+			"lit":      []byte("%%generate:not-a-generator"),
+			"password": []byte("%generate:password"),
+		},
+	}
+	if err := w.MutateCreate(context.TODO(), secret); err != nil {
+		t.Fatalf("got error: %s", err)
+	}
+	if v := string(secret.Data["lit"]); v != "%generate:not-a-generator" {
+		t.Errorf("got invalid unescaped value: %s", v)
+	}
+	// the validating webhook is called with the mutated secret
+	if err := w.ValidateCreate(context.TODO(), secret); err != nil {
+		t.Errorf("got error: %s", err)
+	}
+
+	// a copy of the mutated secret is accepted as well, and keeps the literal
+	secret = &corev1.Secret{
+		ObjectMeta: *secret.ObjectMeta.DeepCopy(),
+		Data: map[string][]byte{
+			"lit": secret.Data["lit"],
+		},
+	}
+	if err := w.MutateCreate(context.TODO(), secret); err != nil {
+		t.Fatalf("got error: %s", err)
+	}
+	if err := w.ValidateCreate(context.TODO(), secret); err != nil {
+		t.Errorf("got error: %s", err)
+	}
+	if v := string(secret.Data["lit"]); v != "%generate:not-a-generator" {
+		t.Errorf("got invalid literal value: %s", v)
+	}
+}
+
+func TestValidateSecretWithMultipleBundles(t *testing.T) {
+	w := NewSecretWebhook()
+	secret := &corev1.Secret{
+		Data: map[string][]byte{
+			"db":    []byte("%generate:mtls:key_prefix=db-"),
+			"cache": []byte("%generate:mtls:key_prefix=cache-"),
+		},
+	}
+	if err := w.ValidateCreate(context.TODO(), secret); err != nil {
+		t.Errorf("got error: %s", err)
+	}
+
+	// bundles producing the same keys are rejected, also on updates (where their keys may already exist)
+	secret.Data["tls"] = []byte("%generate:mtls:key_prefix=db-")
+	for _, oldSecret := range []*corev1.Secret{nil, {Data: map[string][]byte{"db-ca.crt": []byte("value")}}} {
+		err := w.validateSecret(secret, oldSecret)
+		if !apierrors.IsInvalid(err) {
+			t.Fatalf("expected invalid error, but got: %v", err)
+		}
+		t.Logf("ok; got error: %s", err)
+		var fields []string
+		for _, cause := range err.(apierrors.APIStatus).Status().Details.Causes {
+			fields = append(fields, cause.Field)
+		}
+		if len(fields) != 5 || slices.ContainsFunc(fields, func(f string) bool { return f != "data[tls]" }) {
+			t.Errorf("got invalid causes: %v", fields)
+		}
+	}
+}
@@ -0,0 +1,235 @@
+/*
+SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company and secret-generator contributors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package webhook implements the mutating admission webhook replacing placeholders in secrets by generated values.
+package webhook
+
+import (
+	"context"
+	"io"
+
+	"github.com/go-logr/logr"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/sap/secret-generator/pkg/generator"
+)
+
+type SecretWebhook struct {
+	// client is used by generators which need to read cluster objects; may be nil
+	client kubernetes.Interface
+	// registry holds the generators which can be referenced in placeholders
+	registry *generator.Registry
+	// generators optionally restricts the generators which may be used
+	generators []string
+	// prefix of placeholders, if not overridden by annotation
+	prefix string
+	// random is the source of randomness passed to generators; may be nil
+	random io.Reader
+	log    logr.Logger
+	// sequenceNamespace is the namespace of the counters of the sequence generator; if empty, the namespace of the secret is used
+	sequenceNamespace string
+	// softFail records errors of single keys in the secret instead of denying it, if not overridden by annotation
+	softFail bool
+	// gitOps enables GitOps mode, if not overridden by annotation
+	gitOps bool
+	// fieldManager takes over the ownership of generated keys in GitOps mode; if empty, managed fields are not changed
+	fieldManager string
+	// lock locks the generated keys of secrets, if not overridden by annotation
+	lock bool
+	// users and groups which may change locked keys
+	unlockUsers  []string
+	unlockGroups []string
+	// userInfo returns the requester of the processed admission request; if nil, it is taken from the admission request in the context
+	userInfo UserInfoFunc
+	// warningHandler receives warnings about the processed secrets
+	warningHandler WarningHandler
+	// hooks called around the generation of each key
+	preGenerateHooks  []PreGenerateHook
+	postGenerateHooks []PostGenerateHook
+}
+
+// Option configures a SecretWebhook.
+type Option func(w *SecretWebhook)
+
+// WithClient sets the Kubernetes client used by generators which need to read or write cluster objects (e.g. choice, sequence, ssh);
+// without client, these generators fail.
+func WithClient(client kubernetes.Interface) Option {
+	return func(w *SecretWebhook) {
+		w.client = client
+	}
+}
+
+// WithPrefix sets the prefix of placeholders (default %generate); the prefix can still be overridden per secret by annotation.
+func WithPrefix(prefix string) Option {
+	return func(w *SecretWebhook) {
+		w.prefix = prefix
+	}
+}
+
+// WithRegistry sets the registry generators are looked up in (default generator.DefaultRegistry).
+func WithRegistry(registry *generator.Registry) Option {
+	return func(w *SecretWebhook) {
+		w.registry = registry
+	}
+}
+
+// WithGenerators restricts the generator types which may be used in placeholders; other types are rejected as unsupported.
+func WithGenerators(names ...string) Option {
+	return func(w *SecretWebhook) {
+		w.generators = names
+	}
+}
+
+// WithRandom sets the source of randomness for generated values (default crypto/rand.Reader); this is mainly meant for testing,
+// since values are only as unpredictable as the given source. Private keys are always generated from crypto/rand.
+func WithRandom(random io.Reader) Option {
+	return func(w *SecretWebhook) {
+		w.random = random
+	}
+}
+
+// WithSequenceNamespace sets the namespace of the config map holding the counters of the sequence generator (default: the namespace
+// of the secret); setting a fixed namespace makes counters unique across the cluster.
+func WithSequenceNamespace(namespace string) Option {
+	return func(w *SecretWebhook) {
+		w.sequenceNamespace = namespace
+	}
+}
+
+// WithLogger sets the logger (default: discard all logs).
+func WithLogger(log logr.Logger) Option {
+	return func(w *SecretWebhook) {
+		w.log = log
+	}
+}
+
+// WithSoftFail enables soft-fail mode by default (it can still be enabled or disabled per secret by annotation);
+// in soft-fail mode, keys which cannot be generated keep their placeholder, and the errors are recorded in the secret,
+// instead of denying the whole secret.
+func WithSoftFail(enabled bool) Option {
+	return func(w *SecretWebhook) {
+		w.softFail = enabled
+	}
+}
+
+// WithGitOps enables GitOps mode by default (it can still be enabled or disabled per secret by annotation); in GitOps mode,
+// the placeholders of generated keys are recorded in an annotation, and generated keys are retained by default.
+func WithGitOps(enabled bool) Option {
+	return func(w *SecretWebhook) {
+		w.gitOps = enabled
+	}
+}
+
+// WithFieldManager sets the field manager which takes over the ownership of generated keys (in the managed fields of secrets)
+// in GitOps mode, such that server-side applies no longer own (and therefore revert or remove) generated values.
+func WithFieldManager(name string) Option {
+	return func(w *SecretWebhook) {
+		w.fieldManager = name
+	}
+}
+
+// WithLock locks the generated keys of secrets by default (it can still be enabled or disabled per secret by annotation);
+// updates changing locked keys to other values than placeholders are denied, unless the requester is allowed by WithUnlockAllowed().
+func WithLock(enabled bool) Option {
+	return func(w *SecretWebhook) {
+		w.lock = enabled
+	}
+}
+
+// WithUnlockAllowed sets the users and groups which may change locked keys; the requester is determined by the function set by WithUserInfo().
+func WithUnlockAllowed(users []string, groups []string) Option {
+	return func(w *SecretWebhook) {
+		w.unlockUsers = users
+		w.unlockGroups = groups
+	}
+}
+
+// WithUserInfo sets the function returning the requester of the processed admission request; by default, the requester is taken
+// from the admission request passed in the context (see NewContextWithRequest()), and without it, nobody may change locked keys.
+func WithUserInfo(userInfo UserInfoFunc) Option {
+	return func(w *SecretWebhook) {
+		w.userInfo = userInfo
+	}
+}
+
+// WithWarningHandler sets the handler receiving warnings about processed secrets (default: log warnings, and return them
+// in the admission response, if served by the handlers returned by NewMutatingHandler() and NewValidatingHandler()).
+func WithWarningHandler(handler WarningHandler) Option {
+	return func(w *SecretWebhook) {
+		w.warningHandler = handler
+	}
+}
+
+// WithPreGenerateHook adds a hook which is called before each key is generated.
+func WithPreGenerateHook(hook PreGenerateHook) Option {
+	return func(w *SecretWebhook) {
+		w.preGenerateHooks = append(w.preGenerateHooks, hook)
+	}
+}
+
+// WithPostGenerateHook adds a hook which is called after each key was generated.
+func WithPostGenerateHook(hook PostGenerateHook) Option {
+	return func(w *SecretWebhook) {
+		w.postGenerateHooks = append(w.postGenerateHooks, hook)
+	}
+}
+
+func NewSecretWebhook(options ...Option) *SecretWebhook {
+	w := &SecretWebhook{
+		registry: generator.DefaultRegistry,
+		prefix:   DefaultPrefix,
+		log:      logr.Discard(),
+	}
+	for _, option := range options {
+		option(w)
+	}
+	if w.warningHandler == nil {
+		w.warningHandler = func(ctx context.Context, secret *corev1.Secret, message string) {
+			w.log.Info("warning: "+message, "namespace", secret.Namespace, "name", secret.Name)
+			addWarning(ctx, message)
+		}
+	}
+	if w.generators != nil {
+		w.registry = w.registry.Restrict(w.generators...)
+	}
+	return w
+}
+
+// in soft-fail mode, invalid placeholders are not rejected up front, but recorded as errors during generation
+func (w *SecretWebhook) MutateCreate(ctx context.Context, secret *corev1.Secret) error {
+	if !w.isSoftFail(secret) {
+		if err := w.validateSecret(secret, nil); err != nil {
+			return err
+		}
+	}
+	return w.handleCreateSecret(ctx, secret)
+}
+
+// locked keys are checked on mutation only, since the validating webhook sees the mutated secret (e.g. with rotated values)
+func (w *SecretWebhook) MutateUpdate(ctx context.Context, oldSecret *corev1.Secret, newSecret *corev1.Secret) error {
+	if err := w.checkLockedKeys(ctx, newSecret, oldSecret); err != nil {
+		return err
+	}
+	if !w.isSoftFail(newSecret) {
+		if err := w.validateSecret(newSecret, oldSecret); err != nil {
+			return err
+		}
+	}
+	return w.handleUpdateSecret(ctx, newSecret, oldSecret)
+}
+
+func (w *SecretWebhook) ValidateCreate(ctx context.Context, secret *corev1.Secret) error {
+	return w.validateSecret(secret, nil)
+}
+
+func (w *SecretWebhook) ValidateUpdate(ctx context.Context, oldSecret *corev1.Secret, newSecret *corev1.Secret) error {
+	return w.validateSecret(newSecret, oldSecret)
+}
+
+func (w *SecretWebhook) ValidateDelete(ctx context.Context, secret *corev1.Secret) error {
+	return nil
+}
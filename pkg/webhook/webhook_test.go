@@ -0,0 +1,266 @@
+/*
+SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company and secret-generator contributors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package webhook
+
+import (
+	"context"
+	"fmt"
+	"math/rand/v2"
+	"slices"
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/sap/secret-generator/pkg/generator"
+)
+
+func TestNewSecretWebhookWithPrefix(t *testing.T) {
+	w := NewSecretWebhook(WithPrefix("$gen"))
+	secret := &corev1.Secret{
+		Data: map[string][]byte{
+			"password": []byte("$gen:password:length=8"),
+			"other":    []byte("%generate"),
+		},
+	}
+	if err := w.MutateCreate(context.TODO(), secret); err != nil {
+		t.Fatalf("got error: %s", err)
+	}
+	if len(secret.Data["password"]) != 8 || string(secret.Data["other"]) != "%generate" {
+		t.Errorf("got invalid data: %v", secret.Data)
+	}
+}
+
+func TestNewSecretWebhookWithGenerators(t *testing.T) {
+	w := NewSecretWebhook(WithGenerators("uuid"))
+	secret := &corev1.Secret{
+		Data: map[string][]byte{
+			"id": []byte("%generate:uuid"),
+		},
+	}
+	if err := w.MutateCreate(context.TODO(), secret); err != nil {
+		t.Fatalf("got error: %s", err)
+	}
+	secret = &corev1.Secret{
+		Data: map[string][]byte{
+			"password": []byte("%generate"),
+		},
+	}
+	if err := w.MutateCreate(context.TODO(), secret); err == nil {
+		t.Error("expected error for disabled generator, but got none")
+	} else {
+		t.Logf("ok; got error: %s", err)
+	}
+}
+
+func TestNewSecretWebhookWithRandom(t *testing.T) {
+	var values []string
+	for i := 0; i < 2; i++ {
+		w := NewSecretWebhook(WithRandom(rand.NewChaCha8([32]byte{})))
+		secret := &corev1.Secret{
+			Data: map[string][]byte{
+				"id":       []byte("%generate:uuid"),
+				"password": []byte("%generate"),
+			},
+		}
+		if err := w.MutateCreate(context.TODO(), secret); err != nil {
+			t.Fatalf("got error: %s", err)
+		}
+		values = append(values, string(secret.Data["id"])+"/"+string(secret.Data["password"]))
+	}
+	if values[0] != values[1] {
+		t.Errorf("got different values from the same random source: %v", values)
+	}
+}
+
+func TestNewSecretWebhookWithHooks(t *testing.T) {
+	var calls []string
+	w := NewSecretWebhook(
+		WithPreGenerateHook(PreGenerateHookFunc(func(ctx context.Context, event *GenerateEvent) error {
+			calls = append(calls, "pre:"+event.Key+":"+event.Format)
+			if event.Key == "forbidden" {
+				return fmt.Errorf("key %s is not allowed", event.Key)
+			}
+			return nil
+		})),
+		WithPostGenerateHook(PostGenerateHookFunc(func(ctx context.Context, event *GenerateEvent) error {
+			for k := range event.Values {
+				calls = append(calls, "post:"+event.Key+":"+k)
+			}
+			return nil
+		})),
+	)
+	secret := &corev1.Secret{
+		Data: map[string][]byte{
+			"id":       []byte("%generate:uuid"),
+			"password": []byte("existing"),
+		},
+	}
+	oldSecret := &corev1.Secret{
+		Data: map[string][]byte{
+			"password": []byte("existing"),
+			"token":    []byte("existing"),
+		},
+	}
+	secret.Data["token"] = []byte("%generate")
+	if err := w.MutateUpdate(context.TODO(), oldSecret, secret); err != nil {
+		t.Fatalf("got error: %s", err)
+	}
+	if expected := []string{"pre:id:uuid", "post:id:id"}; !slices.Equal(calls, expected) {
+		t.Errorf("got invalid hook calls: %v (expected: %v)", calls, expected)
+	}
+
+	secret = &corev1.Secret{
+		Data: map[string][]byte{
+			"forbidden": []byte("%generate"),
+		},
+	}
+	if err := w.MutateCreate(context.TODO(), secret); err == nil {
+		t.Error("expected error from hook, but got none")
+	} else {
+		t.Logf("ok; got error: %s", err)
+	}
+}
+
+func TestNewSecretWebhookWithWarningHandler(t *testing.T) {
+	registry := generator.NewRegistry()
+	for _, name := range []string{"password", "uuid"} {
+		g, _ := generator.DefaultRegistry.Get(name)
+		if err := registry.Register(g); err != nil {
+			t.Fatalf("got error: %s", err)
+		}
+	}
+	if err := registry.Register(generator.NewGenerator("legacy", generator.Schema{
+		Arguments: []generator.Argument{
+			{Name: "size", Pattern: `\d+`, Deprecated: "use length instead"},
+			{Name: "length", Pattern: `\d+`},
+		},
+	}, func(ctx context.Context, args generator.Arguments) (string, error) {
+		return "legacy", nil
+	})); err != nil {
+		t.Fatalf("got error: %s", err)
+	}
+	var warnings []string
+	w := NewSecretWebhook(WithRegistry(registry), WithWarningHandler(func(ctx context.Context, secret *corev1.Secret, message string) {
+		warnings = append(warnings, message)
+	}))
+
+	secret := &corev1.Secret{
+		Data: map[string][]byte{
+			"password": []byte("%generate:password:length=16"),
+			"legacy":   []byte("%generate:legacy:size=8"),
+		},
+	}
+	if err := w.MutateCreate(context.TODO(), secret); err != nil {
+		t.Fatalf("got error: %s", err)
+	}
+	if len(warnings) != 1 || !strings.Contains(warnings[0], "deprecated") {
+		t.Errorf("got invalid warnings: %v", warnings)
+	}
+	if _, ok := secret.Annotations[AnnotationKeyGenerated]; !ok {
+		t.Errorf("got no %s annotation", AnnotationKeyGenerated)
+	}
+
+	// unchanged placeholder
+	warnings = nil
+	oldSecret := secret.DeepCopy()
+	secret = &corev1.Secret{
+		ObjectMeta: *oldSecret.ObjectMeta.DeepCopy(),
+		Data: map[string][]byte{
+			"password": []byte("%generate:password:length=16"),
+		},
+	}
+	if err := w.MutateUpdate(context.TODO(), oldSecret, secret); err != nil {
+		t.Fatalf("got error: %s", err)
+	}
+	if len(warnings) != 0 {
+		t.Errorf("got unexpected warnings: %v", warnings)
+	}
+
+	// changed placeholder
+	oldSecret = secret.DeepCopy()
+	secret.Data["password"] = []byte("%generate:password:length=32")
+	if err := w.MutateUpdate(context.TODO(), oldSecret, secret); err != nil {
+		t.Fatalf("got error: %s", err)
+	}
+	if len(warnings) != 1 || !strings.Contains(warnings[0], "differs") || len(secret.Data["password"]) != 16 {
+		t.Errorf("got invalid warnings: %v", warnings)
+	}
+
+	// placeholder without record
+	warnings = nil
+	oldSecret = &corev1.Secret{
+		Data: map[string][]byte{
+			"id": []byte("value"),
+		},
+	}
+	secret = &corev1.Secret{
+		Data: map[string][]byte{
+			"id": []byte("%generate:uuid"),
+		},
+	}
+	if err := w.MutateUpdate(context.TODO(), oldSecret, secret); err != nil {
+		t.Fatalf("got error: %s", err)
+	}
+	if len(warnings) != 1 || !strings.Contains(warnings[0], "ignored") {
+		t.Errorf("got invalid warnings: %v", warnings)
+	}
+	if record := getGeneratedKeys(secret)["id"]; record == nil || record.Spec != "uuid" || record.GeneratedAt != nil {
+		t.Errorf("got invalid %s annotation: %s", AnnotationKeyGenerated, secret.Annotations[AnnotationKeyGenerated])
+	}
+}
+
+func TestNewSecretWebhookWithGitOps(t *testing.T) {
+	w := NewSecretWebhook(WithGitOps(true))
+	lastApplied := `{"apiVersion":"v1","kind":"Secret","stringData":{"password":"%generate:password:length=16"}}`
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Annotations: map[string]string{"kubectl.kubernetes.io/last-applied-configuration": lastApplied},
+		},
+		Data: map[string][]byte{
+			"password": []byte("%generate:password:length=16"),
+			"other":    []byte("value"),
+		},
+	}
+	if err := w.MutateCreate(context.TODO(), secret); err != nil {
+		t.Fatalf("got error: %s", err)
+	}
+	if v := secret.Annotations[AnnotationKeyPlaceholders]; v != `{"password":"%generate:password:length=16"}` {
+		t.Errorf("got invalid %s annotation: %s", AnnotationKeyPlaceholders, v)
+	}
+	if secret.Annotations["kubectl.kubernetes.io/last-applied-configuration"] != lastApplied {
+		t.Error("last applied configuration got changed")
+	}
+
+	// re-applying the manifest without the generated key retains it
+	oldSecret := secret.DeepCopy()
+	secret.Data = map[string][]byte{
+		"other": []byte("value"),
+	}
+	if err := w.MutateUpdate(context.TODO(), oldSecret, secret); err != nil {
+		t.Fatalf("got error: %s", err)
+	}
+	if string(secret.Data["password"]) != string(oldSecret.Data["password"]) || secret.Annotations[AnnotationKeyPlaceholders] == "" {
+		t.Errorf("generated key was not retained: %v", secret.Data)
+	}
+
+	// disabled per secret
+	secret = &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Annotations: map[string]string{AnnotationKeyGitOps: "false"},
+		},
+		Data: map[string][]byte{
+			"password": []byte("%generate"),
+		},
+	}
+	if err := w.MutateCreate(context.TODO(), secret); err != nil {
+		t.Fatalf("got error: %s", err)
+	}
+	if _, ok := secret.Annotations[AnnotationKeyPlaceholders]; ok {
+		t.Errorf("got unexpected %s annotation", AnnotationKeyPlaceholders)
+	}
+}